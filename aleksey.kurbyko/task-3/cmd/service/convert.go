@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/config"
+	"github.com/aleksey.kurbyko/task-3/internal/converter"
+	"github.com/aleksey.kurbyko/task-3/internal/validator"
+)
+
+const defaultPrecision = 4
+
+func runConvert(args []string) error {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	layers := config.Bind(flags)
+	inputPath := flags.String("input", "", "Shorthand for -input-file")
+	from := flags.String("from", "", "Source currency char code")
+	target := flags.String("to", "", "Target currency char code")
+	amountText := flags.String("amount", "1", "Amount in source currency")
+	precision := flags.Int("precision", defaultPrecision, "Digits after the decimal point")
+
+	if err := flags.Parse(args); err != nil {
+		return parseError(err)
+	}
+
+	if *inputPath != "" {
+		layers.Override("input-file", *inputPath)
+	}
+
+	cfg, err := layers.Load()
+	if err != nil {
+		return err
+	}
+
+	err = cfg.ValidateInput()
+	if err != nil {
+		return err
+	}
+
+	amount, err := converter.ParseAmount(*amountText)
+	if err != nil {
+		return apperrors.WrapField(apperrors.ErrConfig, apperrors.NoIndex, "amount", err)
+	}
+
+	currencies, err := validator.DecodeFile(context.Background(), cfg.Input, cfg.DecodeOptions())
+	if err != nil {
+		return err
+	}
+
+	rates, err := converter.New(currencies)
+	if err != nil {
+		return err
+	}
+
+	result, err := rates.Convert(*from, *target, amount)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrConfig, err)
+	}
+
+	_, err = fmt.Fprintf(os.Stdout, "%s %s = %s %s\n",
+		amount.FloatString(*precision), *from, result.FloatString(*precision), *target)
+	if err != nil {
+		return apperrors.Wrap(apperrors.ErrWrite, err)
+	}
+
+	return nil
+}
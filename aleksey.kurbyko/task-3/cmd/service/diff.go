@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/config"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+	"github.com/aleksey.kurbyko/task-3/internal/filewriter"
+	"github.com/aleksey.kurbyko/task-3/internal/ratediff"
+	"github.com/aleksey.kurbyko/task-3/internal/validator"
+)
+
+var errDiffArgs = errors.New("diff needs exactly two input files: OLD NEW")
+
+const diffInputs = 2
+
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	threshold := flags.String("threshold", "0", "Flag changes whose absolute percentage reaches this value")
+	format := flags.String("format", ratediff.OutputTable, "Output format: table or json")
+	output := flags.String("output", "", "Write the report to this file instead of stdout")
+	layers := config.Bind(flags)
+
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: %s diff [flags] OLD NEW\n\nflags:\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+
+	inputs, err := parseInterspersed(flags, args)
+	if err != nil {
+		return parseError(err)
+	}
+
+	if len(inputs) != diffInputs {
+		return apperrors.Wrap(apperrors.ErrConfig, errDiffArgs)
+	}
+
+	limit, err := decimal.Parse(*threshold)
+	if err != nil {
+		return apperrors.WrapField(apperrors.ErrConfig, apperrors.NoIndex, "threshold", err)
+	}
+
+	cfg, err := layers.Load()
+	if err != nil {
+		return err
+	}
+
+	previous, previousDate, err := loadSnapshot(inputs[0], cfg)
+	if err != nil {
+		return err
+	}
+
+	current, currentDate, err := loadSnapshot(inputs[1], cfg)
+	if err != nil {
+		return err
+	}
+
+	report := ratediff.Compare(previous, current, limit)
+	report.OldDate, report.NewDate = previousDate, currentDate
+
+	var data bytes.Buffer
+
+	if err := ratediff.Write(&data, report, *format); err != nil {
+		if errors.Is(err, ratediff.ErrUnknownOutput) {
+			return apperrors.Wrap(apperrors.ErrConfig, err)
+		}
+
+		return apperrors.Wrap(apperrors.ErrWrite, err)
+	}
+
+	if *output != "" {
+		return writeOutput(*output, data.Bytes(), filewriter.DefaultOptions())
+	}
+
+	if _, err := os.Stdout.Write(data.Bytes()); err != nil {
+		return apperrors.Wrap(apperrors.ErrWrite, err)
+	}
+
+	return nil
+}
+
+// parseInterspersed accepts flags before, between and after the
+// positional arguments, so "diff a.xml b.xml -threshold 1" does not
+// silently ignore the threshold. Everything after "--" is positional.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+
+		rest := flags.Args()
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+
+		if len(rest) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// loadSnapshot decodes one side of the diff with the input, validation
+// and on-invalid settings of the layered config, as convert and serve do.
+func loadSnapshot(location string, cfg config.Config) ([]dataprocessor.JSONCurrency, string, error) {
+	list, err := validator.DecodeFile(context.Background(), location, cfg.DecodeOptions())
+	if err != nil {
+		return nil, "", err
+	}
+
+	conversion, err := dataprocessor.ConvertToJSON(list, cfg.OnInvalid)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", location, err)
+	}
+
+	return conversion.Currencies, list.Date, nil
+}
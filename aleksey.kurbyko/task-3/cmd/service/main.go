@@ -1,71 +1,166 @@
 package main
 
 import (
-	"encoding/json"
-	"encoding/xml"
+	"bytes"
+	"errors"
 	"flag"
+	"fmt"
 	"os"
-	"path/filepath"
 
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/config"
 	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
 	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
-	"golang.org/x/net/html/charset"
-	"gopkg.in/yaml.v3"
+	"github.com/aleksey.kurbyko/task-3/internal/filewriter"
+	"github.com/aleksey.kurbyko/task-3/internal/writer"
 )
 
-const (
-	dirPerm  = 0o755
-	filePerm = 0o600
-)
+// errRejectsNotWritten marks a run whose output was written but whose
+// rejects file was not.
+var errRejectsNotWritten = errors.New("rejects not written to")
 
 func main() {
-	configPath := flag.String("config", "", "Path to YAML config")
-	flag.Parse()
+	var err error
+
+	command := ""
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	switch command {
+	case "convert":
+		err = runConvert(os.Args[2:])
+	case "diff":
+		err = runDiff(os.Args[2:])
+	case "serve":
+		err = runServe(os.Args[2:])
+	default:
+		err = run(os.Args[1:])
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+	}
+
+	os.Exit(apperrors.ExitCode(err))
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("service", flag.ContinueOnError)
+	layers := config.Bind(flags)
+	printConfig := flags.Bool("print-config", false, "Print the effective configuration and where each value came from")
+	watchMode := flags.Bool("watch", false, "Keep running and reconvert when the input or config changes")
+	interval := flags.Duration("interval", defaultInterval, "How often to poll for changes in watch mode")
+	debounce := flags.Duration("debounce", defaultDebounce, "Quiet period after a change before reconverting")
+
+	if err := flags.Parse(args); err != nil {
+		return parseError(err)
+	}
+
+	if *printConfig {
+		return printEffectiveConfig(layers)
+	}
+
+	if *watchMode {
+		return runWatch(layers, *interval, *debounce)
+	}
+
+	_, err := convertOnce(layers)
+
+	return err
+}
+
+func printEffectiveConfig(layers *config.Flags) error {
+	cfg, err := layers.Load()
+	if err != nil {
+		return err
+	}
+
+	return apperrors.Wrap(apperrors.ErrWrite, config.Print(os.Stdout, cfg))
+}
 
-	configData, err := os.ReadFile(*configPath)
+// convertOnce loads the layered config, runs the whole pipeline and
+// returns the config it used. Output is replaced only when every stage
+// succeeded.
+func convertOnce(layers *config.Flags) (config.Config, error) {
+	cfg, err := layers.Load()
 	if err != nil {
-		panic(err)
+		return cfg, err
 	}
 
-	var paths dataprocessor.FilePaths
+	err = cfg.ValidateInput()
+	if err != nil {
+		return cfg, err
+	}
 
-	err = yaml.Unmarshal(configData, &paths)
+	err = cfg.ValidateOutput()
 	if err != nil {
-		panic(err)
+		return cfg, err
 	}
 
-	inputFile, err := os.Open(paths.Input)
+	outputWriter, err := writer.ForOutput(cfg.OutputFormat, cfg.Output)
 	if err != nil {
-		panic(err)
+		return cfg, err
 	}
 
-	xmlDecoder := xml.NewDecoder(inputFile)
-	xmlDecoder.CharsetReader = charset.NewReaderLabel
+	err = currencyhandler.ValidateFormat(cfg.InputFormat)
+	if err != nil {
+		return cfg, err
+	}
 
-	var currencies currencyhandler.CurrencyList
+	err = currencyhandler.ValidateEncoding(cfg.InputEncoding)
+	if err != nil {
+		return cfg, err
+	}
 
-	err = xmlDecoder.Decode(&currencies)
+	inputFiles, isSeries, err := currencyhandler.ResolveInputs(cfg.Input)
 	if err != nil {
-		panic(err)
+		return cfg, err
 	}
 
-	_ = inputFile.Close()
+	var (
+		outputData bytes.Buffer
+		summary    report
+	)
 
-	currencyhandler.SortCurrencies(&currencies)
-	jsonOutput := dataprocessor.ConvertToJSON(currencies)
+	if isSeries {
+		err = writeSeries(&outputData, &summary, outputWriter, inputFiles, cfg)
+	} else {
+		err = writeSnapshot(&outputData, &summary, outputWriter, inputFiles[0], cfg)
+	}
 
-	outputData, err := json.MarshalIndent(jsonOutput, "", "  ")
 	if err != nil {
-		panic(err)
+		return cfg, err
 	}
 
-	err = os.MkdirAll(filepath.Dir(paths.Output), dirPerm)
+	err = writeOutput(cfg.Output, outputData.Bytes(), cfg.WriteOptions())
 	if err != nil {
-		panic(err)
+		return cfg, err
 	}
 
-	err = os.WriteFile(paths.Output, outputData, filePerm)
+	if cfg.OnInvalid == "" || cfg.OnInvalid == dataprocessor.OnInvalidFail {
+		return cfg, nil
+	}
+
+	rejectsOptions := cfg.WriteOptions()
+	rejectsOptions.Backup = false
+
+	err = summary.flush(cfg.RejectsFile, rejectsOptions)
 	if err != nil {
-		panic(err)
+		return cfg, fmt.Errorf("%w %s: %w", errRejectsNotWritten, cfg.RejectsFile, err)
+	}
+
+	return cfg, nil
+}
+
+func parseError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
 	}
+
+	return apperrors.Wrap(apperrors.ErrConfig, err)
+}
+
+func writeOutput(path string, data []byte, options filewriter.Options) error {
+	return apperrors.Wrap(apperrors.ErrWrite, filewriter.WriteFile(path, data, options))
 }
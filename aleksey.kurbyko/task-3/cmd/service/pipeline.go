@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/config"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/timeseries"
+	"github.com/aleksey.kurbyko/task-3/internal/validator"
+	"github.com/aleksey.kurbyko/task-3/internal/writer"
+)
+
+var errSeriesOption = errors.New("is not supported when several input files are merged")
+
+type emitFunc func(date string, result dataprocessor.ItemResult)
+
+func writeSnapshot(
+	out *bytes.Buffer,
+	summary *report,
+	outputWriter writer.Writer,
+	inputFile string,
+	cfg config.Config,
+) error {
+	var currencies []dataprocessor.JSONCurrency
+
+	err := processFile(inputFile, cfg, summary, func(_ string, result dataprocessor.ItemResult) {
+		currencies = append(currencies, result.Currency)
+	})
+	if err != nil {
+		return err
+	}
+
+	currencies, err = cfg.Filter.Apply(currencies)
+	if err != nil {
+		return err
+	}
+
+	err = dataprocessor.SortCurrencies(currencies, cfg.Sort)
+	if err != nil {
+		return err
+	}
+
+	table, err := cfg.Projection.Table(currencies)
+	if err != nil {
+		return err
+	}
+
+	return apperrors.Wrap(apperrors.ErrWrite, outputWriter.Write(out, table))
+}
+
+// writeSeries merges several daily files into one summary per currency.
+// The filter applies to every file; sort keys and the projection describe
+// daily records, so they are refused rather than silently ignored.
+func writeSeries(
+	out *bytes.Buffer,
+	summary *report,
+	outputWriter writer.Writer,
+	inputFiles []string,
+	cfg config.Config,
+) error {
+	if len(cfg.Sort) > 0 {
+		return apperrors.WrapField(apperrors.ErrConfig, apperrors.NoIndex, "sort", errSeriesOption)
+	}
+
+	if len(cfg.Projection.Fields) > 0 || len(cfg.Projection.Rename) > 0 {
+		return apperrors.WrapField(apperrors.ErrConfig, apperrors.NoIndex, "projection", errSeriesOption)
+	}
+
+	seriesWriter, err := writer.ForSeries(outputWriter)
+	if err != nil {
+		return err
+	}
+
+	series := timeseries.New()
+
+	for _, inputFile := range inputFiles {
+		byDate := make(map[string][]dataprocessor.JSONCurrency)
+
+		err := processFile(inputFile, cfg, summary, func(rawDate string, result dataprocessor.ItemResult) {
+			byDate[rawDate] = append(byDate[rawDate], result.Currency)
+		})
+		if err != nil {
+			return err
+		}
+
+		for rawDate, currencies := range byDate {
+			date, err := currencyhandler.ParseDate(rawDate)
+			if err != nil {
+				return fmt.Errorf("%s: %w", inputFile, err)
+			}
+
+			currencies, err = cfg.Filter.Apply(currencies)
+			if err != nil {
+				return err
+			}
+
+			for _, currency := range currencies {
+				series.Observe(date, currency)
+			}
+		}
+	}
+
+	return apperrors.Wrap(apperrors.ErrWrite, seriesWriter.WriteSeries(out, series.Summaries()))
+}
+
+// processFile streams one input through validation and conversion and
+// hands every accepted record to emit.
+func processFile(inputFile string, cfg config.Config, summary *report, emit emitFunc) error {
+	options := cfg.DecodeOptions()
+	options.Reject = func(index int, item currencyhandler.CurrencyItem, violations []validator.Violation) {
+		summary.addItem(inputFile, dataprocessor.ItemResult{
+			Currency:  dataprocessor.JSONCurrency{},
+			Reject:    violationReject(index, item, violations),
+			Defaulted: false,
+		})
+	}
+
+	records, err := validator.Open(context.Background(), inputFile, options)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = records.Close() }()
+
+	for records.Next() {
+		result, err := dataprocessor.ConvertItem(records.Index(), records.Item(), cfg.OnInvalid)
+		if err != nil {
+			return fmt.Errorf("%s: %w", inputFile, err)
+		}
+
+		summary.addItem(inputFile, result)
+
+		if result.Reject == nil {
+			emit(records.Date(), result)
+		}
+	}
+
+	return records.Err()
+}
+
+func violationReject(
+	index int,
+	item currencyhandler.CurrencyItem,
+	violations []validator.Violation,
+) *dataprocessor.Reject {
+	reasons := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		reasons = append(reasons, violation.String())
+	}
+
+	return &dataprocessor.Reject{
+		Source:   "",
+		Index:    index,
+		CharCode: item.CharCode,
+		Field:    violations[0].Field,
+		Reason:   apperrors.ErrValidate.Error() + ": " + strings.Join(reasons, "; "),
+		Record:   item,
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/filewriter"
+)
+
+type report struct {
+	accepted  int
+	defaulted int
+	rejects   []dataprocessor.Reject
+}
+
+func (r *report) add(source string, conversion dataprocessor.Conversion) {
+	r.accepted += len(conversion.Currencies)
+	r.defaulted += conversion.Defaulted
+
+	for _, reject := range conversion.Rejects {
+		reject.Source = source
+		r.rejects = append(r.rejects, reject)
+	}
+}
+
+func (r *report) addItem(source string, result dataprocessor.ItemResult) {
+	var conversion dataprocessor.Conversion
+
+	conversion.Append(result)
+	r.add(source, conversion)
+}
+
+// flush writes rejected records as JSON lines and prints the counts to
+// stderr. An empty rejects file is still written so that a stale one
+// from a previous run does not linger. An explicitly empty rejects-file
+// only prints the counts.
+func (r *report) flush(rejectsFile string, options filewriter.Options) error {
+	var data bytes.Buffer
+
+	encoder := json.NewEncoder(&data)
+	for _, reject := range r.rejects {
+		if err := encoder.Encode(reject); err != nil {
+			return apperrors.Wrap(apperrors.ErrWrite, err)
+		}
+	}
+
+	if rejectsFile != "" {
+		if err := writeOutput(rejectsFile, data.Bytes(), options); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "accepted: %d, rejected: %d, defaulted: %d\n",
+		r.accepted, len(r.rejects), r.defaulted)
+
+	return nil
+}
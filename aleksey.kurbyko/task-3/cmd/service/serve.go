@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/config"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/httpapi"
+	"github.com/aleksey.kurbyko/task-3/internal/validator"
+)
+
+const (
+	defaultAddr       = "localhost:8080"
+	readHeaderTimeout = 5 * time.Second
+	shutdownTimeout   = 10 * time.Second
+)
+
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	layers := config.Bind(flags)
+	addr := flags.String("addr", defaultAddr, "Address to listen on")
+
+	if err := flags.Parse(args); err != nil {
+		return parseError(err)
+	}
+
+	server := httpapi.New(func(ctx context.Context) (currencyhandler.CurrencyList, []dataprocessor.JSONCurrency, error) {
+		return loadRates(ctx, layers)
+	})
+
+	if err := server.Reload(context.Background()); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	defer signal.Stop(hangup)
+
+	httpServer := &http.Server{Addr: *addr, Handler: server.Handler(), ReadHeaderTimeout: readHeaderTimeout}
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	fmt.Fprintf(os.Stderr, "serve: listening on %s\n", *addr)
+
+	for {
+		select {
+		case err := <-serveErr:
+			return apperrors.Wrap(apperrors.ErrConfig, err)
+		case <-hangup:
+			if err := server.Reload(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "serve: reload: %v (keeping previous rates)\n", err)
+			} else {
+				fmt.Fprintln(os.Stderr, "serve: reloaded")
+			}
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+
+			err := httpServer.Shutdown(shutdownCtx)
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return apperrors.Wrap(apperrors.ErrWrite, err)
+			}
+
+			return nil
+		}
+	}
+}
+
+// loadRates rereads the config as well, so a SIGHUP picks up a new
+// input-file, filter or sort order.
+func loadRates(
+	ctx context.Context,
+	layers *config.Flags,
+) (currencyhandler.CurrencyList, []dataprocessor.JSONCurrency, error) {
+	cfg, err := layers.Load()
+	if err != nil {
+		return currencyhandler.CurrencyList{}, nil, err
+	}
+
+	err = cfg.ValidateInput()
+	if err != nil {
+		return currencyhandler.CurrencyList{}, nil, err
+	}
+
+	list, err := validator.DecodeFile(ctx, cfg.Input, cfg.DecodeOptions())
+	if err != nil {
+		return list, nil, err
+	}
+
+	conversion, err := dataprocessor.ConvertToJSON(list, cfg.OnInvalid)
+	if err != nil {
+		return list, nil, err
+	}
+
+	list.Items = withoutRejects(list.Items, conversion.Rejects)
+
+	currencies, err := cfg.Filter.Apply(conversion.Currencies)
+	if err != nil {
+		return list, nil, err
+	}
+
+	err = dataprocessor.SortCurrencies(currencies, cfg.Sort)
+	if err != nil {
+		return list, nil, err
+	}
+
+	return list, currencies, nil
+}
+
+// withoutRejects drops the records skipped by the on-invalid policy so
+// that /convert does not fail on them either.
+func withoutRejects(
+	items []currencyhandler.CurrencyItem,
+	rejects []dataprocessor.Reject,
+) []currencyhandler.CurrencyItem {
+	if len(rejects) == 0 {
+		return items
+	}
+
+	rejected := make(map[int]bool, len(rejects))
+	for _, reject := range rejects {
+		rejected[reject.Index] = true
+	}
+
+	kept := make([]currencyhandler.CurrencyItem, 0, len(items)-len(rejects))
+
+	for index, item := range items {
+		if !rejected[index] {
+			kept = append(kept, item)
+		}
+	}
+
+	return kept
+}
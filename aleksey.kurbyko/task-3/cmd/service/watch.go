@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/config"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/aleksey.kurbyko/task-3/internal/source"
+	"github.com/aleksey.kurbyko/task-3/internal/watch"
+)
+
+var (
+	errWatchInterval = errors.New("watch interval must be positive")
+	errWatchStdin    = errors.New("watch mode needs a file input, not stdin")
+)
+
+const (
+	defaultInterval = 2 * time.Second
+	defaultDebounce = 500 * time.Millisecond
+)
+
+// runWatch converts once and then polls the config and input files,
+// reconverting after a burst of changes has been quiet for debounce. A
+// failed run is reported and leaves the last good output untouched.
+func runWatch(layers *config.Flags, interval, debounce time.Duration) error {
+	if interval <= 0 {
+		return apperrors.WrapField(apperrors.ErrConfig, apperrors.NoIndex, "interval", errWatchInterval)
+	}
+
+	cfg, err := layers.Load()
+	if err != nil {
+		return err
+	}
+
+	if cfg.Input == source.Stdin {
+		return apperrors.Wrap(apperrors.ErrConfig, errWatchStdin)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	snapshot := watch.NewSnapshot()
+	snapshot.Update(watchedPaths(layers))
+	reconvert(layers)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	quiet := watch.NewDebounce(debounce)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			if quiet.Tick(now, snapshot.Update(watchedPaths(layers))) {
+				reconvert(layers)
+			}
+		}
+	}
+}
+
+func reconvert(layers *config.Flags) {
+	cfg, err := convertOnce(layers)
+
+	switch {
+	case errors.Is(err, errRejectsNotWritten):
+		fmt.Fprintf(os.Stderr, "watch: wrote %s, but %v\n", cfg.Output, err)
+
+		return
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "watch: %v (keeping previous output)\n", err)
+
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "watch: wrote %s\n", cfg.Output)
+}
+
+// watchedPaths lists the config file and the local files behind its input.
+// Network inputs are not polled; an unreadable config still leaves the
+// config itself watched so that fixing it triggers a run.
+func watchedPaths(layers *config.Flags) []string {
+	var paths []string
+	if path := layers.Path(); path != "" {
+		paths = append(paths, path)
+	}
+
+	cfg, err := layers.Load()
+	if err != nil {
+		return paths
+	}
+
+	inputFiles, _, err := currencyhandler.ResolveInputs(cfg.Input)
+	if err != nil {
+		inputFiles = []string{cfg.Input}
+	}
+
+	for _, inputFile := range inputFiles {
+		if path, ok := source.LocalPath(inputFile); ok {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
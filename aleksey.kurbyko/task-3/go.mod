@@ -3,8 +3,13 @@ module github.com/aleksey.kurbyko/task-3
 go 1.22.7
 
 require (
+	github.com/stretchr/testify v1.11.1
 	golang.org/x/net v0.28.0
+	golang.org/x/text v0.17.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
-require golang.org/x/text v0.17.0 // indirect
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+)
@@ -0,0 +1,98 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrConfig    = errors.New("config")
+	ErrInput     = errors.New("input")
+	ErrDecode    = errors.New("decode")
+	ErrValidate  = errors.New("validate")
+	ErrTransform = errors.New("transform")
+	ErrWrite     = errors.New("write")
+)
+
+const (
+	ExitOK = iota
+	ExitUnknown
+	ExitConfig
+	ExitInput
+	ExitDecode
+	ExitValidate
+	ExitTransform
+	ExitWrite
+)
+
+const NoIndex = -1
+
+// StageError ties a failure to the pipeline stage it happened in and,
+// when known, to the Valute record (zero-based index) and field.
+type StageError struct {
+	Stage error
+	Index int
+	Field string
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	message := e.Stage.Error()
+
+	if e.Index != NoIndex {
+		message += fmt.Sprintf(": valute #%d", e.Index)
+	}
+
+	if e.Field != "" {
+		message += ": field " + e.Field
+	}
+
+	return message + ": " + e.Err.Error()
+}
+
+func (e *StageError) Unwrap() []error {
+	return []error{e.Stage, e.Err}
+}
+
+func Wrap(stage error, err error) error {
+	return WrapField(stage, NoIndex, "", err)
+}
+
+func WrapField(stage error, index int, field string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var stageErr *StageError
+	if errors.As(err, &stageErr) && errors.Is(err, stage) {
+		return err
+	}
+
+	return &StageError{
+		Stage: stage,
+		Index: index,
+		Field: field,
+		Err:   err,
+	}
+}
+
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, ErrConfig):
+		return ExitConfig
+	case errors.Is(err, ErrInput):
+		return ExitInput
+	case errors.Is(err, ErrDecode):
+		return ExitDecode
+	case errors.Is(err, ErrValidate):
+		return ExitValidate
+	case errors.Is(err, ErrTransform):
+		return ExitTransform
+	case errors.Is(err, ErrWrite):
+		return ExitWrite
+	default:
+		return ExitUnknown
+	}
+}
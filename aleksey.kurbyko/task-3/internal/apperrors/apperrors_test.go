@@ -0,0 +1,66 @@
+package apperrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/stretchr/testify/require"
+)
+
+var errCause = errors.New("cause")
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, apperrors.Wrap(apperrors.ErrInput, nil))
+
+	err := apperrors.Wrap(apperrors.ErrInput, errCause)
+	require.ErrorIs(t, err, apperrors.ErrInput)
+	require.ErrorIs(t, err, errCause)
+	require.EqualError(t, err, "input: cause")
+
+	require.Same(t, err, apperrors.Wrap(apperrors.ErrInput, err), "the same stage is not wrapped twice")
+
+	rewrapped := apperrors.Wrap(apperrors.ErrWrite, fmt.Errorf("context: %w", err))
+	require.ErrorIs(t, rewrapped, apperrors.ErrWrite)
+	require.ErrorIs(t, rewrapped, apperrors.ErrInput)
+}
+
+func TestWrapField(t *testing.T) {
+	t.Parallel()
+
+	err := apperrors.WrapField(apperrors.ErrTransform, 3, "Value", errCause)
+	require.EqualError(t, err, "transform: valute #3: field Value: cause")
+
+	var stageErr *apperrors.StageError
+
+	require.ErrorAs(t, err, &stageErr)
+	require.Equal(t, 3, stageErr.Index)
+	require.Equal(t, "Value", stageErr.Field)
+
+	require.EqualError(t,
+		apperrors.WrapField(apperrors.ErrConfig, apperrors.NoIndex, "amount", errCause),
+		"config: field amount: cause")
+}
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		err  error
+		want int
+	}{
+		{err: nil, want: apperrors.ExitOK},
+		{err: errCause, want: apperrors.ExitUnknown},
+		{err: apperrors.Wrap(apperrors.ErrConfig, errCause), want: 2},
+		{err: apperrors.Wrap(apperrors.ErrInput, errCause), want: 3},
+		{err: apperrors.Wrap(apperrors.ErrDecode, errCause), want: 4},
+		{err: apperrors.Wrap(apperrors.ErrValidate, errCause), want: 5},
+		{err: apperrors.Wrap(apperrors.ErrTransform, errCause), want: 6},
+		{err: fmt.Errorf("out.json: %w", apperrors.Wrap(apperrors.ErrWrite, errCause)), want: 7},
+	} {
+		require.Equal(t, test.want, apperrors.ExitCode(test.err), "%v", test.err)
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/filewriter"
+	"github.com/aleksey.kurbyko/task-3/internal/validator"
+	"github.com/aleksey.kurbyko/task-3/internal/writer"
+	"gopkg.in/yaml.v3"
+)
+
+const rejectsSuffix = ".rejects.jsonl"
+
+type Config struct {
+	Input         string                  `yaml:"input-file"`
+	InputFormat   string                  `yaml:"input-format"`
+	InputEncoding string                  `yaml:"input-encoding"`
+	Output        string                  `yaml:"output-file"`
+	OutputFormat  string                  `yaml:"output-format"`
+	Sort          []dataprocessor.SortKey `yaml:"sort"`
+	OnInvalid     string                  `yaml:"on-invalid"`
+	RejectsFile   string                  `yaml:"rejects-file"`
+	Validation    validator.Rules         `yaml:"validation"`
+	Filter        dataprocessor.Filter    `yaml:"filter"`
+	Projection    writer.Projection       `yaml:"projection"`
+	DirPerm       filewriter.Mode         `yaml:"dir-permissions"`
+	FilePerm      filewriter.Mode         `yaml:"file-permissions"`
+	Backup        bool                    `yaml:"backup"`
+
+	// Path and Sources record where the effective values came from.
+	Path    string            `yaml:"-"`
+	Sources map[string]Source `yaml:"-"`
+}
+
+func read(path string) (Config, error) {
+	var cfg Config
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("read: %w", err))
+	}
+
+	err = yaml.Unmarshal(data, &cfg)
+	if err != nil {
+		return cfg, apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("parse: %w", err))
+	}
+
+	err = cfg.Projection.Validate()
+	if err != nil {
+		return cfg, err
+	}
+
+	var present map[string]any
+
+	err = yaml.Unmarshal(data, &present)
+	if err != nil {
+		return cfg, apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("parse: %w", err))
+	}
+
+	cfg.Sources = make(map[string]Source, len(present))
+	for key := range present {
+		cfg.Sources[key] = SourceFile
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) applyDefaults() {
+	if _, given := c.Sources["rejects-file"]; !given && c.RejectsFile == "" && c.Output != "" {
+		c.RejectsFile = c.Output + rejectsSuffix
+		c.Sources["rejects-file"] = SourceDefault
+	}
+}
+
+func (c Config) WriteOptions() filewriter.Options {
+	return filewriter.Options{DirPerm: c.DirPerm, FilePerm: c.FilePerm, Backup: c.Backup}
+}
+
+// DecodeOptions is how every command decodes and validates the input.
+func (c Config) DecodeOptions() validator.Options {
+	return validator.Options{
+		Format:    c.InputFormat,
+		Encoding:  c.InputEncoding,
+		Rules:     c.Validation,
+		OnInvalid: c.OnInvalid,
+		Reject:    nil,
+	}
+}
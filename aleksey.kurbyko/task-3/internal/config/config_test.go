@@ -0,0 +1,186 @@
+package config_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/config"
+	"github.com/aleksey.kurbyko/task-3/internal/writer"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func load(t *testing.T, args ...string) (config.Config, error) {
+	t.Helper()
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	layers := config.Bind(flags)
+	require.NoError(t, flags.Parse(args))
+
+	return layers.Load()
+}
+
+func TestLoadProjection(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfig(t, `
+output-file: out.json
+projection:
+  fields: [char_code, unit_rate]
+  rename:
+    unit_rate: rate
+`)
+
+	cfg, err := load(t, "-config", path)
+	require.NoError(t, err)
+	require.Equal(t, writer.Projection{
+		Fields: []string{"char_code", "unit_rate"},
+		Rename: map[string]string{"unit_rate": "rate"},
+	}, cfg.Projection)
+}
+
+func TestLoadProjectionRejectsDuplicateKeys(t *testing.T) {
+	t.Parallel()
+
+	for name, content := range map[string]string{
+		"two renames":         "projection:\n  rename:\n    value: rate\n    unit_rate: rate\n",
+		"rename onto a field": "projection:\n  fields: [value, unit_rate]\n  rename:\n    value: unit_rate\n",
+		"repeated field":      "projection:\n  fields: [value, value]\n",
+		"unknown field":       "projection:\n  fields: [rate]\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := load(t, "-config", writeConfig(t, content))
+			require.ErrorIs(t, err, apperrors.ErrConfig)
+		})
+	}
+
+	_, err := load(t, "-config", writeConfig(t, "projection:\n  rename:\n    value: rate\n    unit_rate: rate\n"))
+	require.ErrorIs(t, err, writer.ErrDuplicateKey)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, `
+input-file: from-yaml.xml
+output-file: from-yaml.json
+output-format: yaml
+on-invalid: skip
+`)
+
+	t.Setenv(config.EnvName("output-file"), "from-env.json")
+	t.Setenv(config.EnvName("output-format"), "csv")
+	t.Setenv(config.EnvName("backup"), "true")
+
+	cfg, err := load(t, "-config", path, "-output-format", "json", "-rejects-file", "")
+	require.NoError(t, err)
+
+	for key, want := range map[string]config.Source{
+		"input-file":    config.SourceFile,
+		"output-file":   config.SourceEnv,
+		"output-format": config.SourceFlag,
+		"on-invalid":    config.SourceFile,
+		"rejects-file":  config.SourceFlag,
+		"backup":        config.SourceEnv,
+	} {
+		require.Equal(t, want, cfg.Sources[key], key)
+	}
+
+	require.Equal(t, "from-yaml.xml", cfg.Input)
+	require.Equal(t, "from-env.json", cfg.Output)
+	require.Equal(t, "json", cfg.OutputFormat)
+	require.Equal(t, "skip", cfg.OnInvalid)
+	require.Empty(t, cfg.RejectsFile, "an explicit empty flag still overrides")
+	require.True(t, cfg.Backup)
+	require.Equal(t, path, cfg.Path)
+}
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	path := writeConfig(t, "output-file: out.json\n")
+
+	t.Setenv(config.EnvConfig, path)
+
+	cfg, err := load(t)
+	require.NoError(t, err)
+	require.Equal(t, path, cfg.Path)
+	require.Equal(t, config.SourceEnv, cfg.Sources["config"])
+	require.Equal(t, "out.json.rejects.jsonl", cfg.RejectsFile)
+	require.Equal(t, config.SourceDefault, cfg.Sources["rejects-file"])
+
+	t.Setenv(config.EnvName("dir-permissions"), "rwx")
+
+	_, err = load(t)
+	require.ErrorIs(t, err, apperrors.ErrConfig)
+	require.ErrorContains(t, err, "dir-permissions")
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := load(t, "-config", filepath.Join(t.TempDir(), "missing.yaml"))
+	require.ErrorIs(t, err, apperrors.ErrConfig)
+
+	_, err = load(t, "-config", writeConfig(t, "input-file: [not, a, string]\n"))
+	require.ErrorIs(t, err, apperrors.ErrConfig)
+
+	_, err = load(t, "-config", writeConfig(t, "backup: true\n"), "-backup=maybe")
+	require.Error(t, err)
+}
+
+func TestValidateInputOutput(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	cfg, err := load(t, "-config", writeConfig(t, "input-file: "+filepath.Join(dir, "missing.xml")+"\n"), "-output-file", dir)
+	require.NoError(t, err)
+	require.ErrorIs(t, cfg.ValidateInput(), config.ErrInputNotFound)
+	require.ErrorIs(t, cfg.ValidateOutput(), config.ErrOutputIsDir)
+
+	cfg, err = load(t, "-config", writeConfig(t, "input-file: https://example.com/daily.xml\n"))
+	require.NoError(t, err)
+	require.NoError(t, cfg.ValidateInput(), "remote inputs are not checked up front")
+	require.ErrorIs(t, cfg.ValidateOutput(), config.ErrMissingValue)
+}
+
+func TestPrint(t *testing.T) {
+	t.Parallel()
+
+	path := writeConfig(t, `
+output-file: out.json
+filter:
+  include: [USD]
+projection:
+  fields: [char_code]
+`)
+
+	cfg, err := load(t, "-config", path, "-backup")
+	require.NoError(t, err)
+
+	var out strings.Builder
+
+	require.NoError(t, config.Print(&out, cfg))
+
+	printed := out.String()
+	require.Regexp(t, `config\s+`+regexp.QuoteMeta(path)+`\s+flag`, printed)
+	require.Regexp(t, `output-file\s+out\.json\s+yaml`, printed)
+	require.Regexp(t, `backup\s+true\s+flag`, printed)
+	require.Regexp(t, `file-permissions\s+0600\s+default`, printed)
+	require.Regexp(t, `sort\s+\[\{key: unit_rate, direction: desc\}\]\s+default`, printed)
+	require.Regexp(t, `filter\s+\{include: \[USD\].*\}\s+yaml`, printed)
+	require.Regexp(t, `validation\s+\{min-records: 1.*\}\s+default`, printed)
+	require.Regexp(t, `projection\s+\{fields: \[char_code\].*\}\s+yaml`, printed)
+}
@@ -0,0 +1,382 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/filewriter"
+	"github.com/aleksey.kurbyko/task-3/internal/source"
+	"github.com/aleksey.kurbyko/task-3/internal/validator"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrMissingValue  = errors.New("value is required")
+	ErrInputNotFound = errors.New("input file does not exist")
+	ErrOutputIsDir   = errors.New("output path is a directory")
+)
+
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "yaml"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+const (
+	DefaultPath = "config.yaml"
+	EnvPrefix   = "CBR_"
+	EnvConfig   = EnvPrefix + "CONFIG"
+	configKey   = "config"
+	tableWidth  = 2
+	globMeta    = "*?["
+)
+
+// setting is one scalar key that can come from YAML, the environment or
+// a flag. The env name is derived from the key: input-file reads
+// CBR_INPUT_FILE.
+type setting struct {
+	key   string
+	usage string
+	field func(cfg *Config) any
+}
+
+func settings() []setting {
+	return []setting{
+		{key: "input-file", usage: "Input source (path, glob, directory, -, URL or archive.zip#member)",
+			field: func(cfg *Config) any { return &cfg.Input }},
+		{key: "input-format", usage: "Input feed format: auto, cbr-xml, ecb-xml or cbr-json",
+			field: func(cfg *Config) any { return &cfg.InputFormat }},
+		{key: "input-encoding", usage: "Input text encoding such as windows-1251 or utf-8, detected when empty",
+			field: func(cfg *Config) any { return &cfg.InputEncoding }},
+		{key: "output-file", usage: "Output path",
+			field: func(cfg *Config) any { return &cfg.Output }},
+		{key: "output-format", usage: "Output format, detected from the extension when empty",
+			field: func(cfg *Config) any { return &cfg.OutputFormat }},
+		{key: "on-invalid", usage: "Invalid record policy: fail, skip or default",
+			field: func(cfg *Config) any { return &cfg.OnInvalid }},
+		{key: "rejects-file", usage: "Where skipped records are written; empty to not write them",
+			field: func(cfg *Config) any { return &cfg.RejectsFile }},
+		{key: "dir-permissions", usage: "Mode for created directories, e.g. 0755",
+			field: func(cfg *Config) any { return &cfg.DirPerm }},
+		{key: "file-permissions", usage: "Mode for written files, e.g. 0600",
+			field: func(cfg *Config) any { return &cfg.FilePerm }},
+		{key: "backup", usage: "Keep the previous output as <output-file>.bak",
+			field: func(cfg *Config) any { return &cfg.Backup }},
+	}
+}
+
+func (s setting) boolean() bool {
+	_, isBool := s.field(&Config{}).(*bool)
+
+	return isBool
+}
+
+func (s setting) set(cfg *Config, value string) error {
+	switch target := s.field(cfg).(type) {
+	case *string:
+		*target = value
+	case *bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("parse bool: %w", err)
+		}
+
+		*target = parsed
+	case *filewriter.Mode:
+		return target.UnmarshalText([]byte(value))
+	}
+
+	return nil
+}
+
+func (s setting) get(cfg Config) string {
+	options := cfg.WriteOptions().WithDefaults()
+
+	switch target := s.field(&cfg).(type) {
+	case *string:
+		return *target
+	case *bool:
+		return strconv.FormatBool(*target)
+	case *filewriter.Mode:
+		if target == &cfg.DirPerm {
+			return options.DirPerm.String()
+		}
+
+		return options.FilePerm.String()
+	default:
+		return ""
+	}
+}
+
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// flagValue remembers whether a flag was given at all, so that an
+// explicit empty value still overrides the lower layers.
+type flagValue struct {
+	value   string
+	given   bool
+	boolean bool
+}
+
+func (v *flagValue) String() string {
+	return v.value
+}
+
+func (v *flagValue) Set(value string) error {
+	v.value, v.given = value, true
+
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.boolean
+}
+
+// Flags is the command-line layer of the configuration.
+type Flags struct {
+	path   *flagValue
+	values map[string]*flagValue
+}
+
+// Bind registers -config and one flag per setting on flags.
+func Bind(flags *flag.FlagSet) *Flags {
+	bound := &Flags{path: &flagValue{value: "", given: false, boolean: false}, values: make(map[string]*flagValue)}
+
+	flags.Var(bound.path, configKey, "Path to YAML config (default $"+EnvConfig+" or "+DefaultPath+" if present)")
+
+	for _, item := range settings() {
+		value := &flagValue{value: "", given: false, boolean: item.boolean()}
+		bound.values[item.key] = value
+		flags.Var(value, item.key, item.usage+" ($"+EnvName(item.key)+")")
+	}
+
+	return bound
+}
+
+// Override sets a value as if it had been given on the command line.
+func (f *Flags) Override(key, value string) {
+	if flagged, ok := f.values[key]; ok {
+		_ = flagged.Set(value)
+	}
+}
+
+// Path is the config file the YAML layer reads, or "" when there is none.
+func (f *Flags) Path() string {
+	switch {
+	case f.path.given:
+		return f.path.value
+	case os.Getenv(EnvConfig) != "":
+		return os.Getenv(EnvConfig)
+	case fileExists(DefaultPath):
+		return DefaultPath
+	default:
+		return ""
+	}
+}
+
+// Load builds the effective configuration: flags override environment
+// variables, which override the YAML file. Without -config or
+// CBR_CONFIG, config.yaml is read only if it exists.
+func (f *Flags) Load() (Config, error) {
+	cfg, err := f.readFile()
+	if err != nil {
+		return cfg, err
+	}
+
+	for _, item := range settings() {
+		if value, ok := os.LookupEnv(EnvName(item.key)); ok {
+			if err := cfg.apply(item, value, SourceEnv); err != nil {
+				return cfg, err
+			}
+		}
+
+		if flagged := f.values[item.key]; flagged.given {
+			if err := cfg.apply(item, flagged.value, SourceFlag); err != nil {
+				return cfg, err
+			}
+		}
+	}
+
+	cfg.applyDefaults()
+
+	return cfg, nil
+}
+
+func (f *Flags) readFile() (Config, error) {
+	path, origin := f.Path(), SourceDefault
+
+	switch {
+	case f.path.given:
+		origin = SourceFlag
+	case os.Getenv(EnvConfig) != "":
+		origin = SourceEnv
+	}
+
+	if path == "" {
+		var cfg Config
+
+		cfg.Sources = map[string]Source{configKey: SourceDefault}
+
+		return cfg, nil
+	}
+
+	cfg, err := read(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	cfg.Path = path
+	cfg.Sources[configKey] = origin
+
+	return cfg, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+
+	return err == nil
+}
+
+func (c *Config) apply(item setting, value string, origin Source) error {
+	if err := item.set(c, value); err != nil {
+		return apperrors.WrapField(apperrors.ErrConfig, apperrors.NoIndex, item.key,
+			fmt.Errorf("from %s: %w", origin, err))
+	}
+
+	c.Sources[item.key] = origin
+
+	return nil
+}
+
+// ValidateInput checks up front that input-file is set and, for a plain
+// local path, that it exists.
+func (c Config) ValidateInput() error {
+	if c.Input == "" {
+		return apperrors.WrapField(apperrors.ErrConfig, apperrors.NoIndex, "input-file", ErrMissingValue)
+	}
+
+	path, local := source.LocalPath(c.Input)
+	if !local || strings.ContainsAny(path, globMeta) {
+		return nil
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return apperrors.WrapField(apperrors.ErrConfig, apperrors.NoIndex, "input-file",
+			fmt.Errorf("%w: %s", ErrInputNotFound, path))
+	}
+
+	return nil
+}
+
+func (c Config) ValidateOutput() error {
+	if c.Output == "" {
+		return apperrors.WrapField(apperrors.ErrConfig, apperrors.NoIndex, "output-file", ErrMissingValue)
+	}
+
+	if info, err := os.Stat(c.Output); err == nil && info.IsDir() {
+		return apperrors.WrapField(apperrors.ErrConfig, apperrors.NoIndex, "output-file",
+			fmt.Errorf("%w: %s", ErrOutputIsDir, c.Output))
+	}
+
+	return nil
+}
+
+// section is a structured key that only the YAML layer sets.
+type section struct {
+	key   string
+	value func(cfg Config) any
+}
+
+func sections() []section {
+	return []section{
+		{key: "sort", value: func(cfg Config) any {
+			if len(cfg.Sort) == 0 {
+				return dataprocessor.DefaultSortKeys()
+			}
+
+			return cfg.Sort
+		}},
+		{key: "filter", value: func(cfg Config) any { return cfg.Filter }},
+		{key: "validation", value: func(cfg Config) any {
+			rules := cfg.Validation
+			if rules.MinRecords == nil {
+				minRecords := validator.DefaultMinRecords
+				rules.MinRecords = &minRecords
+			}
+
+			return rules
+		}},
+		{key: "projection", value: func(cfg Config) any { return cfg.Projection }},
+	}
+}
+
+// flowYAML renders value on one line, e.g. {include: [USD], exclude: []}.
+func flowYAML(value any) (string, error) {
+	var node yaml.Node
+
+	if err := node.Encode(value); err != nil {
+		return "", fmt.Errorf("encode: %w", err)
+	}
+
+	node.Style = yaml.FlowStyle
+
+	data, err := yaml.Marshal(&node)
+	if err != nil {
+		return "", fmt.Errorf("encode: %w", err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Print writes every effective setting, scalars and sections alike, with
+// the layer each one came from.
+func Print(out io.Writer, cfg Config) error {
+	table := tabwriter.NewWriter(out, 0, 0, tableWidth, ' ', 0)
+
+	path := cfg.Path
+	if path == "" {
+		path = "(none)"
+	}
+
+	fmt.Fprintf(table, "%s\t%s\t%s\n", configKey, path, cfg.source(configKey))
+
+	for _, item := range settings() {
+		fmt.Fprintf(table, "%s\t%s\t%s\n", item.key, item.get(cfg), cfg.source(item.key))
+	}
+
+	for _, item := range sections() {
+		value, err := flowYAML(item.value(cfg))
+		if err != nil {
+			return fmt.Errorf("print config: %s: %w", item.key, err)
+		}
+
+		fmt.Fprintf(table, "%s\t%s\t%s\n", item.key, value, cfg.source(item.key))
+	}
+
+	if err := table.Flush(); err != nil {
+		return fmt.Errorf("print config: %w", err)
+	}
+
+	return nil
+}
+
+func (c Config) source(key string) Source {
+	if origin, ok := c.Sources[key]; ok {
+		return origin
+	}
+
+	return SourceDefault
+}
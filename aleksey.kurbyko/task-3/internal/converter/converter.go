@@ -0,0 +1,108 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+)
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrInvalidNumber   = errors.New("invalid number")
+	ErrInvalidRate     = errors.New("invalid rate")
+)
+
+type Converter struct {
+	rates map[string]*big.Rat
+}
+
+func New(list currencyhandler.CurrencyList) (*Converter, error) {
+	rates := make(map[string]*big.Rat, len(list.Items)+1)
+	rates[list.BaseCurrency()] = big.NewRat(1, 1)
+
+	for index, item := range list.Items {
+		rate, err := unitRate(index, item)
+		if err != nil {
+			return nil, err
+		}
+
+		rates[strings.ToUpper(strings.TrimSpace(item.CharCode))] = rate
+	}
+
+	return &Converter{rates: rates}, nil
+}
+
+// Convert returns amount of the from currency expressed in the to
+// currency, going through the base currency rate of both (RUB for CBR
+// feeds, EUR for ECB).
+func (c *Converter) Convert(from, to string, amount *big.Rat) (*big.Rat, error) {
+	fromRate, err := c.rate(from)
+	if err != nil {
+		return nil, err
+	}
+
+	toRate, err := c.rate(to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(big.Rat).Mul(amount, fromRate)
+
+	return result.Quo(result, toRate), nil
+}
+
+func (c *Converter) rate(charCode string) (*big.Rat, error) {
+	rate, ok := c.rates[strings.ToUpper(strings.TrimSpace(charCode))]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCurrency, charCode)
+	}
+
+	return rate, nil
+}
+
+func ParseAmount(value string) (*big.Rat, error) {
+	amount, err := decimal.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidNumber, err)
+	}
+
+	return amount.Rat(), nil
+}
+
+func unitRate(index int, item currencyhandler.CurrencyItem) (*big.Rat, error) {
+	if item.VunitRate != "" {
+		rate, err := positive(item.VunitRate)
+
+		return rate, apperrors.WrapField(apperrors.ErrTransform, index, "VunitRate", err)
+	}
+
+	value, err := positive(item.Value)
+	if err != nil {
+		return nil, apperrors.WrapField(apperrors.ErrTransform, index, "Value", err)
+	}
+
+	nominal, err := item.ParseNominal()
+	if err != nil {
+		return nil, apperrors.WrapField(apperrors.ErrTransform, index, "Nominal", err)
+	}
+
+	return value.Quo(value, big.NewRat(int64(nominal), 1)), nil
+}
+
+func positive(value string) (*big.Rat, error) {
+	rate, err := ParseAmount(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if rate.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRate, value)
+	}
+
+	return rate, nil
+}
@@ -0,0 +1,105 @@
+package converter_test
+
+import (
+	"encoding/xml"
+	"math/big"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/converter"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/stretchr/testify/require"
+)
+
+func amount(t *testing.T, text string) *big.Rat {
+	t.Helper()
+
+	parsed, err := converter.ParseAmount(text)
+	require.NoError(t, err)
+
+	return parsed
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	rates, err := converter.New(currencyhandler.CurrencyList{
+		XMLName: xml.Name{Space: "", Local: "ValCurs"},
+		Date:    "18.10.2026",
+		Name:    "",
+		Base:    "",
+		Items: []currencyhandler.CurrencyItem{
+			{ID: "", NumCode: "", CharCode: "USD", Nominal: "1", Name: "", Value: "90", VunitRate: ""},
+			{ID: "", NumCode: "", CharCode: "EUR", Nominal: "1", Name: "", Value: "100", VunitRate: ""},
+			{ID: "", NumCode: "", CharCode: "JPY", Nominal: "100", Name: "", Value: "60", VunitRate: ""},
+			{ID: "", NumCode: "", CharCode: "KZT", Nominal: "100", Name: "", Value: "17", VunitRate: "0,18"},
+		},
+	})
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		from, to, amount, want string
+	}{
+		{from: "USD", to: "EUR", amount: "10", want: "9"},
+		{from: "eur", to: " usd ", amount: "9", want: "10"},
+		{from: "USD", to: "RUB", amount: "1,5", want: "135"},
+		{from: "RUB", to: "JPY", amount: "0.6", want: "1"},
+		{from: "KZT", to: "RUB", amount: "100", want: "18"},
+		{from: "USD", to: "USD", amount: "1 000", want: "1000"},
+	} {
+		result, err := rates.Convert(test.from, test.to, amount(t, test.amount))
+		require.NoError(t, err)
+		require.Equal(t, 0, result.Cmp(amount(t, test.want)), "%s %s -> %s = %s", test.amount, test.from, test.to, result)
+	}
+
+	_, err = rates.Convert("USD", "XXX", amount(t, "1"))
+	require.ErrorIs(t, err, converter.ErrUnknownCurrency)
+
+	third, err := rates.Convert("EUR", "USD", amount(t, "1"))
+	require.NoError(t, err)
+	require.Equal(t, "10/9", third.String(), "conversion stays exact")
+}
+
+func TestConvertECBBase(t *testing.T) {
+	t.Parallel()
+
+	// ECB items are already inverted into euros per unit.
+	rates, err := converter.New(currencyhandler.CurrencyList{
+		XMLName: xml.Name{Space: "", Local: "ValCurs"},
+		Date:    "18.10.2026",
+		Name:    "",
+		Base:    currencyhandler.BaseEUR,
+		Items:   []currencyhandler.CurrencyItem{{ID: "", NumCode: "", CharCode: "USD", Nominal: "", Name: "", Value: "0,8", VunitRate: ""}},
+	})
+	require.NoError(t, err)
+
+	result, err := rates.Convert("EUR", "USD", amount(t, "100"))
+	require.NoError(t, err)
+	require.Equal(t, "125", result.FloatString(0))
+
+	_, err = rates.Convert("RUB", "USD", amount(t, "1"))
+	require.ErrorIs(t, err, converter.ErrUnknownCurrency)
+}
+
+func TestNewRejectsBadRates(t *testing.T) {
+	t.Parallel()
+
+	for name, bad := range map[string]currencyhandler.CurrencyItem{
+		"value":     {ID: "", NumCode: "", CharCode: "USD", Nominal: "1", Name: "", Value: "abc", VunitRate: ""},
+		"zero":      {ID: "", NumCode: "", CharCode: "USD", Nominal: "1", Name: "", Value: "0", VunitRate: ""},
+		"nominal":   {ID: "", NumCode: "", CharCode: "USD", Nominal: "-1", Name: "", Value: "90", VunitRate: ""},
+		"vunitrate": {ID: "", NumCode: "", CharCode: "USD", Nominal: "1", Name: "", Value: "90", VunitRate: "-2"},
+	} {
+		_, err := converter.New(currencyhandler.CurrencyList{
+			XMLName: xml.Name{Space: "", Local: "ValCurs"},
+			Date:    "18.10.2026",
+			Name:    "",
+			Base:    "",
+			Items:   []currencyhandler.CurrencyItem{bad},
+		})
+		require.ErrorIs(t, err, apperrors.ErrTransform, name)
+	}
+
+	_, err := converter.ParseAmount("ten")
+	require.ErrorIs(t, err, converter.ErrInvalidNumber)
+}
@@ -0,0 +1,71 @@
+package currencyhandler
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+type cbrJSONDocument struct {
+	Date   string                   `json:"Date"`
+	Valute map[string]cbrJSONValute `json:"Valute"`
+}
+
+type cbrJSONValute struct {
+	ID       string      `json:"ID"`
+	NumCode  string      `json:"NumCode"`
+	CharCode string      `json:"CharCode"`
+	Nominal  json.Number `json:"Nominal"`
+	Name     string      `json:"Name"`
+	Value    json.Number `json:"Value"`
+}
+
+// cbrJSONFormat decodes the daily_json.js feed. Numbers are kept as
+// their literal text so that no float rounding creeps in; records are
+// ordered by CharCode because the feed is a JSON object.
+type cbrJSONFormat struct{}
+
+func (cbrJSONFormat) Records(reader io.Reader, source string) Records {
+	var document cbrJSONDocument
+
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&document); err != nil {
+		return failedRecords(BaseRUB, source, err)
+	}
+
+	codes := make([]string, 0, len(document.Valute))
+	for code := range document.Valute {
+		codes = append(codes, code)
+	}
+
+	sort.Strings(codes)
+
+	records := &sliceRecords{base: BaseRUB, items: make([]datedItem, 0, len(codes)), position: 0, err: nil}
+
+	for index, code := range codes {
+		valute := document.Valute[code]
+
+		charCode := valute.CharCode
+		if charCode == "" {
+			charCode = code
+		}
+
+		records.items = append(records.items, datedItem{
+			date:  document.Date,
+			index: index,
+			item: CurrencyItem{
+				ID:        valute.ID,
+				NumCode:   valute.NumCode,
+				CharCode:  charCode,
+				Nominal:   valute.Nominal.String(),
+				Name:      valute.Name,
+				Value:     valute.Value.String(),
+				VunitRate: "",
+			},
+		})
+	}
+
+	return records
+}
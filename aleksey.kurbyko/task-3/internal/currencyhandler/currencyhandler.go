@@ -2,49 +2,105 @@ package currencyhandler
 
 import (
 	"encoding/xml"
-	"sort"
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+)
+
+var (
+	ErrInvalidNominal = errors.New("nominal must be a positive integer")
+	ErrInvalidDate    = errors.New("invalid date")
+)
+
+const (
+	defaultNominal = 1
+	BaseRUB        = "RUB"
+	BaseEUR        = "EUR"
 )
 
 type CurrencyList struct {
 	XMLName xml.Name       `xml:"ValCurs"`
+	Date    string         `xml:"Date,attr"`
+	Name    string         `xml:"name,attr"`
+	Base    string         `xml:"-"`
 	Items   []CurrencyItem `xml:"Valute"`
 }
 
 type CurrencyItem struct {
-	NumCode  string `xml:"NumCode"`
-	CharCode string `xml:"CharCode"`
-	Value    string `xml:"Value"`
+	ID        string `xml:"ID,attr"`
+	NumCode   string `xml:"NumCode"`
+	CharCode  string `xml:"CharCode"`
+	Nominal   string `xml:"Nominal"`
+	Name      string `xml:"Name"`
+	Value     string `xml:"Value"`
+	VunitRate string `xml:"VunitRate"`
 }
 
-func (list CurrencyList) Len() int {
-	return len(list.Items)
-}
+func (item CurrencyItem) ParseNominal() (int, error) {
+	if item.Nominal == "" {
+		return defaultNominal, nil
+	}
 
-func (list CurrencyList) Swap(i, j int) {
-	list.Items[i], list.Items[j] = list.Items[j], list.Items[i]
+	nominal, err := strconv.Atoi(strings.TrimSpace(item.Nominal))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidNominal, err)
+	}
+
+	if nominal <= 0 {
+		return 0, fmt.Errorf("%w: %d", ErrInvalidNominal, nominal)
+	}
+
+	return nominal, nil
 }
 
-func (list CurrencyList) Less(i, j int) bool {
-	firstValue, err1 := strconv.ParseFloat(list.Items[i].Value, 64)
-	secondValue, err2 := strconv.ParseFloat(list.Items[j].Value, 64)
+func (item CurrencyItem) UnitRate() (decimal.Decimal, error) {
+	if item.VunitRate != "" {
+		return decimal.Parse(item.VunitRate)
+	}
 
-	if err1 != nil {
-		panic(err1)
+	value, err := decimal.Parse(item.Value)
+	if err != nil {
+		return value, err
 	}
 
-	if err2 != nil {
-		panic(err2)
+	nominal, err := item.ParseNominal()
+	if err != nil {
+		return value, err
 	}
 
-	return firstValue < secondValue
+	return value.QuoRound(decimal.FromInt(int64(nominal)), decimal.DivisionScale)
 }
 
-func SortCurrencies(list *CurrencyList) {
-	for i := range list.Items {
-		list.Items[i].Value = strings.ReplaceAll(strings.TrimSpace(list.Items[i].Value), ",", ".")
+func (list CurrencyList) ParseDate() (time.Time, error) {
+	return ParseDate(list.Date)
+}
+
+func (list CurrencyList) BaseCurrency() string {
+	if list.Base == "" {
+		return BaseRUB
+	}
+
+	return list.Base
+}
+
+// ParseDate accepts the day formats used by the supported feeds: CBR XML
+// ("02.01.2006"), ECB ("2006-01-02") and CBR JSON (RFC 3339 timestamp,
+// reduced to its calendar day).
+func ParseDate(raw string) (time.Time, error) {
+	text := strings.TrimSpace(raw)
+
+	for _, layout := range []string{"02.01.2006", time.DateOnly, time.RFC3339} {
+		date, err := time.Parse(layout, text)
+		if err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
 	}
 
-	sort.Sort(sort.Reverse(list))
+	return time.Time{}, apperrors.WrapField(apperrors.ErrDecode, apperrors.NoIndex, "Date",
+		fmt.Errorf("%w: %q", ErrInvalidDate, raw))
 }
@@ -0,0 +1,78 @@
+package currencyhandler
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+)
+
+type ecbEnvelope struct {
+	Cube struct {
+		Days []ecbDay `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+type ecbDay struct {
+	Time  string    `xml:"time,attr"`
+	Rates []ecbRate `xml:"Cube"`
+}
+
+type ecbRate struct {
+	Currency string `xml:"currency,attr"`
+	Rate     string `xml:"rate,attr"`
+}
+
+// ecbXMLFormat decodes the ECB eurofxref feeds (daily and historical).
+// ECB quotes foreign units per one euro, so each rate is inverted into
+// the euro price of one unit and EUR becomes the base currency.
+type ecbXMLFormat struct{}
+
+func (ecbXMLFormat) Records(reader io.Reader, source string) Records {
+	var envelope ecbEnvelope
+
+	decoder := xml.NewDecoder(reader)
+	decoder.CharsetReader = utf8Charset
+
+	if err := decoder.Decode(&envelope); err != nil {
+		return failedRecords(BaseEUR, source, err)
+	}
+
+	records := &sliceRecords{base: BaseEUR, items: nil, position: 0, err: nil}
+
+	for _, day := range envelope.Cube.Days {
+		for index, rate := range day.Rates {
+			item, err := invertECBRate(rate)
+			if err != nil {
+				return failedRecords(BaseEUR, source, fmt.Errorf("%s %s: %w", day.Time, rate.Currency, err))
+			}
+
+			records.items = append(records.items, datedItem{date: day.Time, index: index, item: item})
+		}
+	}
+
+	return records
+}
+
+func invertECBRate(rate ecbRate) (CurrencyItem, error) {
+	perEuro, err := decimal.Parse(rate.Rate)
+	if err != nil {
+		return CurrencyItem{}, err
+	}
+
+	unitRate, err := decimal.FromInt(1).QuoRound(perEuro, decimal.DivisionScale)
+	if err != nil {
+		return CurrencyItem{}, err
+	}
+
+	return CurrencyItem{
+		ID:        "",
+		NumCode:   "",
+		CharCode:  rate.Currency,
+		Nominal:   "1",
+		Name:      "",
+		Value:     unitRate.String(),
+		VunitRate: unitRate.String(),
+	}, nil
+}
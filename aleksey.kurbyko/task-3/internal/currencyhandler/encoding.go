@@ -0,0 +1,145 @@
+package currencyhandler
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+var ErrUnknownEncoding = errors.New("unknown input encoding")
+
+const EncodingAuto = "auto"
+
+var (
+	bomUTF8    = []byte{0xef, 0xbb, 0xbf}
+	bomUTF16LE = []byte{0xff, 0xfe}
+	bomUTF16BE = []byte{0xfe, 0xff}
+
+	prologEncoding = regexp.MustCompile(`^\s*<\?xml[^>]*?encoding\s*=\s*["']([^"']+)["']`)
+)
+
+// ValidateEncoding checks an input-encoding override; empty and auto mean
+// detection.
+func ValidateEncoding(label string) error {
+	if isAuto(label) {
+		return nil
+	}
+
+	_, err := lookupEncoding(label)
+
+	return apperrors.Wrap(apperrors.ErrConfig, err)
+}
+
+func isAuto(label string) bool {
+	return label == "" || strings.EqualFold(label, EncodingAuto)
+}
+
+func lookupEncoding(label string) (encoding.Encoding, error) {
+	found, _ := charset.Lookup(label)
+	if found == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEncoding, label)
+	}
+
+	return found, nil
+}
+
+// normalize returns the input transcoded to UTF-8, so the feed decoders
+// never see another encoding. Unless label overrides it, the encoding is
+// taken from a byte order mark, then guessed: non-ASCII text that is valid
+// UTF-8 is UTF-8 whatever the XML prolog claims (Excel re-saves keep the
+// old declaration), NUL bytes next to the first character mean UTF-16
+// without a BOM, and otherwise the prolog decides. A sample that is all
+// ASCII says nothing, since the first non-ASCII byte may sit past it, so
+// only without a prolog is it read as UTF-8; anything else falls back to
+// windows-1251, the legacy CBR encoding.
+func normalize(reader *bufio.Reader, label string) (io.Reader, error) {
+	head, err := reader.Peek(sniffSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("peek: %w", err)
+	}
+
+	detected, bomSize := detectEncoding(head, len(head) == sniffSize)
+
+	if !isAuto(label) {
+		detected, err = lookupEncoding(label)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := reader.Discard(bomSize); err != nil {
+		return nil, fmt.Errorf("skip byte order mark: %w", err)
+	}
+
+	if detected == unicode.UTF8 {
+		return reader, nil
+	}
+
+	return transform.NewReader(reader, detected.NewDecoder()), nil
+}
+
+func detectEncoding(head []byte, truncated bool) (encoding.Encoding, int) {
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		return unicode.UTF8, len(bomUTF8)
+	case bytes.HasPrefix(head, bomUTF16LE):
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), len(bomUTF16LE)
+	case bytes.HasPrefix(head, bomUTF16BE):
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), len(bomUTF16BE)
+	case len(head) > 1 && head[0] != 0 && head[1] == 0:
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), 0
+	case len(head) > 1 && head[0] == 0 && head[1] != 0:
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), 0
+	case !isASCII(head) && validUTF8(head, truncated):
+		return unicode.UTF8, 0
+	}
+
+	if match := prologEncoding.FindSubmatch(head); match != nil {
+		if declared, _ := charset.Lookup(string(match[1])); declared != nil {
+			return declared, 0
+		}
+	}
+
+	if isASCII(head) {
+		return unicode.UTF8, 0
+	}
+
+	return charmap.Windows1251, 0
+}
+
+func isASCII(data []byte) bool {
+	for _, value := range data {
+		if value >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validUTF8 ignores a rune cut in half at the end of a truncated sample.
+func validUTF8(data []byte, truncated bool) bool {
+	for cut := 0; truncated && cut < utf8.UTFMax-1 && !utf8.Valid(data) && len(data) > 0; cut++ {
+		data = data[:len(data)-1]
+	}
+
+	return utf8.Valid(data)
+}
+
+// utf8Charset lets encoding/xml accept any declared encoding: the input
+// has already been transcoded by normalize.
+func utf8Charset(_ string, input io.Reader) (io.Reader, error) {
+	return input, nil
+}
@@ -0,0 +1,86 @@
+package currencyhandler_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const wantName = "Доллар США"
+
+func fixture(name string) string {
+	return filepath.Join("testdata", "encoding", name)
+}
+
+func TestDecodeFileDetectsEncoding(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{
+		"cp1251-prolog.xml",
+		"cp1251-no-prolog.xml",
+		"utf8-bom.xml",
+		"utf8-no-prolog.xml",
+		"utf8-mislabeled.xml",
+		"utf16le-bom.xml",
+		"utf16be-bom.xml",
+		"utf16le-no-bom.xml",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			list, err := currencyhandler.DecodeFile(context.Background(), fixture(name), "", "")
+			require.NoError(t, err)
+			require.Equal(t, "18.10.2026", list.Date)
+			require.Len(t, list.Items, 1)
+			require.Equal(t, wantName, list.Items[0].Name)
+			require.Equal(t, "90,5567", list.Items[0].Value)
+		})
+	}
+}
+
+func TestDecodeFileEncodingOverride(t *testing.T) {
+	t.Parallel()
+
+	list, err := currencyhandler.DecodeFile(context.Background(), fixture("cp1251-no-prolog.xml"), "", "windows-1251")
+	require.NoError(t, err)
+	require.Equal(t, wantName, list.Items[0].Name)
+
+	list, err = currencyhandler.DecodeFile(context.Background(), fixture("utf8-no-prolog.xml"), "", "windows-1251")
+	require.NoError(t, err)
+	require.NotEqual(t, wantName, list.Items[0].Name)
+
+	_, err = currencyhandler.DecodeFile(context.Background(), fixture("utf8-no-prolog.xml"), "", "klingon")
+	require.ErrorIs(t, err, apperrors.ErrConfig)
+	require.ErrorIs(t, err, currencyhandler.ErrUnknownEncoding)
+}
+
+func TestDecodeFileProlog(t *testing.T) {
+	t.Parallel()
+
+	name, err := charmap.Windows1251.NewEncoder().String(wantName)
+	require.NoError(t, err)
+
+	// The first non-ASCII byte lies past the detection sample, so only the
+	// prolog can tell the encoding.
+	data := `<?xml version="1.0" encoding="windows-1251"?>` + "\n" +
+		`<ValCurs Date="18.10.2026" name="Foreign Currency Market">` + "\n" +
+		"<!-- " + strings.Repeat("padding ", 10*1024) + "-->\n" +
+		`<Valute ID="R01235"><NumCode>840</NumCode><CharCode>USD</CharCode>` +
+		`<Nominal>1</Nominal><Name>` + name + `</Name><Value>90,5567</Value></Valute>` + "\n" +
+		"</ValCurs>\n"
+
+	path := filepath.Join(t.TempDir(), "late-cyrillic.xml")
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	list, err := currencyhandler.DecodeFile(context.Background(), path, "", "")
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	require.Equal(t, wantName, list.Items[0].Name)
+}
@@ -0,0 +1,271 @@
+package currencyhandler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/source"
+)
+
+var (
+	ErrUnknownInputFormat = errors.New("unknown input format")
+	ErrUndetectedFormat   = errors.New("cannot detect input format")
+)
+
+const (
+	FormatAuto    = "auto"
+	FormatCBRXML  = "cbr-xml"
+	FormatECBXML  = "ecb-xml"
+	FormatCBRJSON = "cbr-json"
+
+	ecbRootElement = "Envelope"
+	sniffSize      = 64 * 1024
+)
+
+// Records is a sequence of currency records in the internal model,
+// produced by one of the feed decoders.
+type Records interface {
+	Next() bool
+	Item() CurrencyItem
+	Index() int
+	Date() string
+	Base() string
+	Err() error
+	Close() error
+}
+
+// Format turns a UTF-8 feed into Records. source is only used in messages.
+type Format interface {
+	Records(reader io.Reader, source string) Records
+}
+
+func LookupFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case FormatCBRXML:
+		return cbrXMLFormat{}, nil
+	case FormatECBXML:
+		return ecbXMLFormat{}, nil
+	case FormatCBRJSON:
+		return cbrJSONFormat{}, nil
+	default:
+		return nil, apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("%w: %q", ErrUnknownInputFormat, name))
+	}
+}
+
+func ValidateFormat(name string) error {
+	if name == "" || strings.EqualFold(name, FormatAuto) {
+		return nil
+	}
+
+	_, err := LookupFormat(name)
+
+	return err
+}
+
+// Open reads location through the input source layer and decodes it
+// with the named format, or detects the format from the document root
+// when name is empty or "auto".
+func Open(ctx context.Context, location string, name string, encoding string) (Records, error) {
+	raw, err := source.Open(ctx, location)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrInput, err)
+	}
+
+	records, err := Decode(raw, name, encoding, location)
+	if err != nil {
+		_ = raw.Close()
+
+		return nil, err
+	}
+
+	return closingRecords{Records: records, closer: raw}, nil
+}
+
+// Decode reads records of the named format from reader, detecting the
+// format when name is empty or auto and the text encoding when encoding
+// is. The caller keeps ownership of reader.
+func Decode(reader io.Reader, name string, encoding string, location string) (Records, error) {
+	if err := ValidateEncoding(encoding); err != nil {
+		return nil, err
+	}
+
+	text, err := normalize(bufio.NewReaderSize(reader, sniffSize), encoding)
+	if err != nil {
+		return nil, apperrors.Wrap(apperrors.ErrDecode, fmt.Errorf("%s: %w", location, err))
+	}
+
+	buffered := bufio.NewReaderSize(text, sniffSize)
+
+	if name == "" || strings.EqualFold(name, FormatAuto) {
+		var detected string
+
+		detected, err = DetectFormat(buffered)
+		if err != nil {
+			return nil, apperrors.Wrap(apperrors.ErrDecode, fmt.Errorf("%s: %w", location, err))
+		}
+
+		name = detected
+	}
+
+	format, err := LookupFormat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return format.Records(buffered, location), nil
+}
+
+func DetectFormat(reader *bufio.Reader) (string, error) {
+	head, err := reader.Peek(sniffSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return "", fmt.Errorf("peek: %w", err)
+	}
+
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		return FormatCBRJSON, nil
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(head))
+	decoder.CharsetReader = utf8Charset
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", ErrUndetectedFormat
+		}
+
+		if element, ok := token.(xml.StartElement); ok {
+			if element.Name.Local == ecbRootElement {
+				return FormatECBXML, nil
+			}
+
+			return FormatCBRXML, nil
+		}
+	}
+}
+
+func DecodeFile(ctx context.Context, location string, format string, encoding string) (CurrencyList, error) {
+	records, err := Open(ctx, location, format, encoding)
+	if err != nil {
+		return CurrencyList{}, err
+	}
+
+	defer func() { _ = records.Close() }()
+
+	return Collect(records)
+}
+
+// Collect drains records into a CurrencyList. The list takes its Date
+// from the first record.
+func Collect(records Records) (CurrencyList, error) {
+	list := CurrencyList{
+		XMLName: xml.Name{Space: "", Local: rootElement},
+		Date:    "",
+		Name:    "",
+		Base:    records.Base(),
+		Items:   nil,
+	}
+
+	for records.Next() {
+		if len(list.Items) == 0 {
+			list.Date = records.Date()
+		}
+
+		list.Items = append(list.Items, records.Item())
+	}
+
+	return list, records.Err()
+}
+
+type cbrXMLFormat struct{}
+
+func (cbrXMLFormat) Records(reader io.Reader, source string) Records {
+	return NewStream(reader, source)
+}
+
+type closingRecords struct {
+	Records
+
+	closer io.Closer
+}
+
+func (r closingRecords) Close() error {
+	if err := r.closer.Close(); err != nil {
+		return apperrors.Wrap(apperrors.ErrInput, err)
+	}
+
+	return nil
+}
+
+type datedItem struct {
+	date  string
+	index int
+	item  CurrencyItem
+}
+
+// sliceRecords serves formats that are decoded in one go.
+type sliceRecords struct {
+	base     string
+	items    []datedItem
+	position int
+	err      error
+}
+
+func (r *sliceRecords) Next() bool {
+	if r.err != nil || r.position >= len(r.items) {
+		return false
+	}
+
+	r.position++
+
+	return true
+}
+
+func (r *sliceRecords) current() datedItem {
+	if r.position == 0 {
+		return datedItem{date: "", index: apperrors.NoIndex, item: CurrencyItem{}}
+	}
+
+	return r.items[r.position-1]
+}
+
+func (r *sliceRecords) Item() CurrencyItem {
+	return r.current().item
+}
+
+func (r *sliceRecords) Index() int {
+	return r.current().index
+}
+
+func (r *sliceRecords) Date() string {
+	return r.current().date
+}
+
+func (r *sliceRecords) Base() string {
+	return r.base
+}
+
+func (r *sliceRecords) Err() error {
+	return r.err
+}
+
+func (r *sliceRecords) Close() error {
+	return nil
+}
+
+func failedRecords(base string, source string, err error) *sliceRecords {
+	return &sliceRecords{
+		base:     base,
+		items:    nil,
+		position: 0,
+		err:      apperrors.Wrap(apperrors.ErrDecode, fmt.Errorf("%s: %w", source, err)),
+	}
+}
@@ -0,0 +1,125 @@
+package currencyhandler_test
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ecbFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+  <gesmes:subject>Reference rates</gesmes:subject>
+  <Cube>
+    <Cube time='2026-10-16'>
+      <Cube currency='USD' rate='1.25'/>
+    </Cube>
+    <Cube time='2026-10-17'>
+      <Cube currency='USD' rate='1.6'/>
+      <Cube currency='JPY' rate='160'/>
+    </Cube>
+  </Cube>
+</gesmes:Envelope>`
+
+	cbrJSONFeed = `{
+  "Date": "2026-10-18T11:30:00+03:00",
+  "Valute": {
+    "USD": {"ID": "R01235", "NumCode": "840", "CharCode": "USD", "Nominal": 1, "Name": "Dollar", "Value": 90.5567},
+    "JPY": {"ID": "R01820", "NumCode": "392", "CharCode": "JPY", "Nominal": 100, "Name": "Yen", "Value": 60.102}
+  }
+}`
+
+	cbrXMLFeed = `<ValCurs Date="18.10.2026" name="Foreign Currency Market">
+  <Valute ID="R01235"><NumCode>840</NumCode><CharCode>USD</CharCode><Nominal>1</Nominal><Value>90,5567</Value></Valute>
+</ValCurs>`
+)
+
+func TestDetectFormat(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]string{
+		ecbFeed:                        currencyhandler.FormatECBXML,
+		cbrJSONFeed:                    currencyhandler.FormatCBRJSON,
+		"\xef\xbb\xbf  " + cbrJSONFeed: currencyhandler.FormatCBRJSON,
+		cbrXMLFeed:                     currencyhandler.FormatCBRXML,
+	} {
+		detected, err := currencyhandler.DetectFormat(bufio.NewReader(strings.NewReader(input)))
+		require.NoError(t, err)
+		require.Equal(t, want, detected)
+	}
+
+	_, err := currencyhandler.DetectFormat(bufio.NewReader(strings.NewReader("plain text")))
+	require.ErrorIs(t, err, currencyhandler.ErrUndetectedFormat)
+}
+
+type record struct {
+	date, charCode, nominal, value, vunitRate string
+}
+
+func decodeAll(t *testing.T, input, format string) (string, []record) {
+	t.Helper()
+
+	records, err := currencyhandler.Decode(strings.NewReader(input), format, "", "feed")
+	require.NoError(t, err)
+
+	var got []record
+
+	for records.Next() {
+		item := records.Item()
+		got = append(got, record{
+			date:      records.Date(),
+			charCode:  item.CharCode,
+			nominal:   item.Nominal,
+			value:     item.Value,
+			vunitRate: item.VunitRate,
+		})
+	}
+
+	require.NoError(t, records.Err())
+
+	return records.Base(), got
+}
+
+func TestDecodeECB(t *testing.T) {
+	t.Parallel()
+
+	base, got := decodeAll(t, ecbFeed, "")
+	require.Equal(t, currencyhandler.BaseEUR, base)
+	require.Equal(t, []record{
+		{date: "2026-10-16", charCode: "USD", nominal: "1", value: "0.8", vunitRate: "0.8"},
+		{date: "2026-10-17", charCode: "USD", nominal: "1", value: "0.625", vunitRate: "0.625"},
+		{date: "2026-10-17", charCode: "JPY", nominal: "1", value: "0.00625", vunitRate: "0.00625"},
+	}, got, "rates are inverted into euros per unit")
+}
+
+func TestDecodeCBRJSON(t *testing.T) {
+	t.Parallel()
+
+	base, got := decodeAll(t, cbrJSONFeed, currencyhandler.FormatCBRJSON)
+	require.Equal(t, currencyhandler.BaseRUB, base)
+	require.Equal(t, []record{
+		{date: "2026-10-18T11:30:00+03:00", charCode: "JPY", nominal: "100", value: "60.102", vunitRate: ""},
+		{date: "2026-10-18T11:30:00+03:00", charCode: "USD", nominal: "1", value: "90.5567", vunitRate: ""},
+	}, got, "records come sorted by CharCode with the literal numbers")
+}
+
+func TestLookupFormat(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, currencyhandler.ValidateFormat(""))
+	require.NoError(t, currencyhandler.ValidateFormat("AUTO"))
+	require.NoError(t, currencyhandler.ValidateFormat("ECB-XML"))
+
+	err := currencyhandler.ValidateFormat("csv")
+	require.ErrorIs(t, err, currencyhandler.ErrUnknownInputFormat)
+	require.ErrorIs(t, err, apperrors.ErrConfig)
+
+	records, err := currencyhandler.Decode(strings.NewReader("{broken"), currencyhandler.FormatCBRJSON, "", "feed.js")
+	require.NoError(t, err)
+	require.False(t, records.Next())
+	require.ErrorIs(t, records.Err(), apperrors.ErrDecode)
+}
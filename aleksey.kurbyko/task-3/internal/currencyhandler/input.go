@@ -0,0 +1,61 @@
+package currencyhandler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/source"
+)
+
+var ErrNoInputFiles = errors.New("no input files matched")
+
+const globMeta = "*?["
+
+// ResolveInputs expands a directory or a glob pattern into the list of
+// daily files it covers. The second result reports whether the input
+// describes a set of files rather than a single snapshot.
+func ResolveInputs(input string) ([]string, bool, error) {
+	if source.IsSingle(input) {
+		return []string{input}, false, nil
+	}
+
+	if strings.ContainsAny(input, globMeta) {
+		matches, err := filepath.Glob(input)
+		if err != nil {
+			return nil, true, apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("expand %q: %w", input, err))
+		}
+
+		return checkMatches(input, matches)
+	}
+
+	info, err := os.Stat(input)
+	if err != nil {
+		return nil, false, apperrors.Wrap(apperrors.ErrInput, err)
+	}
+
+	if !info.IsDir() {
+		return []string{input}, false, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(input, "*.xml"))
+	if err != nil {
+		return nil, true, apperrors.Wrap(apperrors.ErrInput, fmt.Errorf("list %q: %w", input, err))
+	}
+
+	return checkMatches(input, matches)
+}
+
+func checkMatches(input string, matches []string) ([]string, bool, error) {
+	if len(matches) == 0 {
+		return nil, true, apperrors.Wrap(apperrors.ErrInput, fmt.Errorf("%w: %s", ErrNoInputFiles, input))
+	}
+
+	sort.Strings(matches)
+
+	return matches, true, nil
+}
@@ -0,0 +1,61 @@
+package currencyhandler_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveInputs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"2026-10-02.xml", "2026-10-01.xml", "notes.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("<ValCurs/>"), 0o600))
+	}
+
+	first, second := filepath.Join(dir, "2026-10-01.xml"), filepath.Join(dir, "2026-10-02.xml")
+
+	files, isSeries, err := currencyhandler.ResolveInputs(dir)
+	require.NoError(t, err)
+	require.True(t, isSeries)
+	require.Equal(t, []string{first, second}, files, "a directory yields its XML files in name order")
+
+	files, isSeries, err = currencyhandler.ResolveInputs(filepath.Join(dir, "*-02.xml"))
+	require.NoError(t, err)
+	require.True(t, isSeries)
+	require.Equal(t, []string{second}, files)
+
+	files, isSeries, err = currencyhandler.ResolveInputs(first)
+	require.NoError(t, err)
+	require.False(t, isSeries)
+	require.Equal(t, []string{first}, files)
+
+	_, _, err = currencyhandler.ResolveInputs(filepath.Join(dir, "*.json"))
+	require.ErrorIs(t, err, currencyhandler.ErrNoInputFiles)
+	require.ErrorIs(t, err, apperrors.ErrInput)
+
+	_, _, err = currencyhandler.ResolveInputs(t.TempDir())
+	require.ErrorIs(t, err, currencyhandler.ErrNoInputFiles)
+}
+
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+
+	want := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+	for _, raw := range []string{"18.10.2026", "2026-10-18", " 2026-10-18 ", "2026-10-18T11:30:00+03:00"} {
+		date, err := currencyhandler.ParseDate(raw)
+		require.NoError(t, err, raw)
+		require.Equal(t, want, date, raw)
+	}
+
+	_, err := currencyhandler.ParseDate("18/10/2026")
+	require.ErrorIs(t, err, currencyhandler.ErrInvalidDate)
+	require.ErrorIs(t, err, apperrors.ErrDecode)
+}
@@ -0,0 +1,164 @@
+package currencyhandler
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+)
+
+var ErrNoValCurs = errors.New("no ValCurs element")
+
+const (
+	rootElement   = "ValCurs"
+	valuteElement = "Valute"
+)
+
+// Stream walks a CBR document token by token and yields one Valute at a
+// time, so only the current record is held in memory. Archive files
+// with several ValCurs blocks are supported: Date and Index always refer
+// to the block the current record belongs to.
+type Stream struct {
+	decoder *xml.Decoder
+	closer  io.Closer
+	source  string
+
+	date    string
+	name    string
+	inRoot  bool
+	seen    bool
+	index   int
+	current CurrencyItem
+	err     error
+}
+
+func NewStream(reader io.Reader, source string) *Stream {
+	decoder := xml.NewDecoder(reader)
+	decoder.CharsetReader = utf8Charset
+
+	return &Stream{
+		decoder: decoder,
+		closer:  nil,
+		source:  source,
+		date:    "",
+		name:    "",
+		inRoot:  false,
+		seen:    false,
+		index:   -1,
+		current: CurrencyItem{},
+		err:     nil,
+	}
+}
+
+func (s *Stream) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	for {
+		token, err := s.decoder.Token()
+		if errors.Is(err, io.EOF) {
+			if !s.seen {
+				s.fail(ErrNoValCurs)
+			}
+
+			return false
+		}
+
+		if err != nil {
+			s.fail(err)
+
+			return false
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			if s.handleStart(element) {
+				return s.err == nil
+			}
+
+			if s.err != nil {
+				return false
+			}
+		case xml.EndElement:
+			if element.Name.Local == rootElement {
+				s.inRoot = false
+			}
+		}
+	}
+}
+
+func (s *Stream) handleStart(element xml.StartElement) bool {
+	switch {
+	case element.Name.Local == rootElement:
+		s.enterRoot(element)
+
+		return false
+	case element.Name.Local == valuteElement && s.inRoot:
+		s.index++
+		s.current = CurrencyItem{}
+
+		if err := s.decoder.DecodeElement(&s.current, &element); err != nil {
+			s.fail(err)
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Stream) enterRoot(element xml.StartElement) {
+	s.inRoot = true
+	s.seen = true
+	s.index = -1
+	s.date = ""
+	s.name = ""
+
+	for _, attr := range element.Attr {
+		switch attr.Name.Local {
+		case "Date":
+			s.date = attr.Value
+		case "name":
+			s.name = attr.Value
+		}
+	}
+}
+
+func (s *Stream) fail(err error) {
+	s.err = apperrors.Wrap(apperrors.ErrDecode, fmt.Errorf("%s: %w", s.source, err))
+}
+
+func (s *Stream) Item() CurrencyItem {
+	return s.current
+}
+
+func (s *Stream) Index() int {
+	return s.index
+}
+
+func (s *Stream) Date() string {
+	return s.date
+}
+
+func (s *Stream) Base() string {
+	return BaseRUB
+}
+
+func (s *Stream) Err() error {
+	return s.err
+}
+
+func (s *Stream) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+
+	if err := s.closer.Close(); err != nil {
+		return apperrors.Wrap(apperrors.ErrInput, err)
+	}
+
+	return nil
+}
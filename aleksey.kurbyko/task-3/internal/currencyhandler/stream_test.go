@@ -0,0 +1,72 @@
+package currencyhandler_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/stretchr/testify/require"
+)
+
+const archive = `<?xml version="1.0" encoding="UTF-8"?>
+<Archive>
+  <ValCurs Date="01.10.2026" name="Foreign Currency Market">
+    <Valute ID="R01235"><NumCode>840</NumCode><CharCode>USD</CharCode><Nominal>1</Nominal><Value>90,1</Value></Valute>
+    <Valute ID="R01239"><NumCode>978</NumCode><CharCode>EUR</CharCode><Nominal>1</Nominal><Value>99,2</Value></Valute>
+  </ValCurs>
+  <ValCurs Date="02.10.2026" name="Foreign Currency Market">
+    <Valute ID="R01235"><NumCode>840</NumCode><CharCode>USD</CharCode><Nominal>1</Nominal><Value>91,3</Value></Valute>
+  </ValCurs>
+</Archive>`
+
+func TestStreamArchive(t *testing.T) {
+	t.Parallel()
+
+	type seen struct {
+		date, charCode, value string
+		index                 int
+	}
+
+	stream := currencyhandler.NewStream(strings.NewReader(archive), "archive.xml")
+
+	var got []seen
+
+	for stream.Next() {
+		item := stream.Item()
+		got = append(got, seen{date: stream.Date(), charCode: item.CharCode, value: item.Value, index: stream.Index()})
+	}
+
+	require.NoError(t, stream.Err())
+	require.NoError(t, stream.Close())
+	require.Equal(t, []seen{
+		{date: "01.10.2026", charCode: "USD", value: "90,1", index: 0},
+		{date: "01.10.2026", charCode: "EUR", value: "99,2", index: 1},
+		{date: "02.10.2026", charCode: "USD", value: "91,3", index: 0},
+	}, got)
+}
+
+func TestStreamErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, input := range map[string]string{
+		"no ValCurs": `<Rates><Valute><CharCode>USD</CharCode></Valute></Rates>`,
+		"truncated":  `<ValCurs Date="01.10.2026"><Valute><CharCode>USD</CharCode>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			stream := currencyhandler.NewStream(strings.NewReader(input), "broken.xml")
+			for stream.Next() {
+				require.Fail(t, "unexpected record", stream.Item().CharCode)
+			}
+
+			require.ErrorIs(t, stream.Err(), apperrors.ErrDecode)
+			require.ErrorContains(t, stream.Err(), "broken.xml")
+		})
+	}
+
+	stream := currencyhandler.NewStream(strings.NewReader("<Rates/>"), "empty.xml")
+	require.False(t, stream.Next())
+	require.ErrorIs(t, stream.Err(), currencyhandler.ErrNoValCurs)
+}
@@ -1,54 +1,188 @@
 package dataprocessor
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
 	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
 )
 
-type FilePaths struct {
-	Input  string `yaml:"input-file"`
-	Output string `yaml:"output-file"`
-}
+var ErrUnknownPolicy = errors.New("unknown on-invalid policy")
+
+const (
+	OnInvalidFail    = "fail"
+	OnInvalidSkip    = "skip"
+	OnInvalidDefault = "default"
+)
 
 type JSONCurrency struct {
-	NumCode  int     `json:"num_code"`
-	CharCode string  `json:"char_code"`
-	Value    float64 `json:"value"`
+	ID       string          `json:"id,omitempty"   xml:"id,attr,omitempty" yaml:"id,omitempty"`
+	NumCode  int             `json:"num_code"       xml:"num_code"          yaml:"num_code"`
+	CharCode string          `json:"char_code"      xml:"char_code"         yaml:"char_code"`
+	Name     string          `json:"name,omitempty" xml:"name,omitempty"    yaml:"name,omitempty"`
+	Nominal  int             `json:"nominal"        xml:"nominal"           yaml:"nominal"`
+	Value    decimal.Decimal `json:"value"          xml:"value"             yaml:"value"`
+	UnitRate decimal.Decimal `json:"unit_rate"      xml:"unit_rate"         yaml:"unit_rate"`
+}
+
+type Reject struct {
+	Source   string                       `json:"source,omitempty"`
+	Index    int                          `json:"index"`
+	CharCode string                       `json:"char_code"`
+	Field    string                       `json:"field"`
+	Reason   string                       `json:"reason"`
+	Record   currencyhandler.CurrencyItem `json:"record"`
+}
+
+type ItemResult struct {
+	Currency  JSONCurrency
+	Reject    *Reject
+	Defaulted bool
+}
+
+type Conversion struct {
+	Currencies []JSONCurrency
+	Rejects    []Reject
+	Defaulted  int
+}
+
+func ValidatePolicy(onInvalid string) error {
+	switch onInvalid {
+	case "", OnInvalidFail, OnInvalidSkip, OnInvalidDefault:
+		return nil
+	default:
+		return apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("%w: %q", ErrUnknownPolicy, onInvalid))
+	}
 }
 
-func processCurrency(currency currencyhandler.CurrencyItem) JSONCurrency {
-	var result JSONCurrency
+// processCurrency parses every field independently so that a record with
+// several broken fields reports all of them. Broken fields are left at
+// their fallback values, which is what the "default" policy keeps.
+func processCurrency(index int, currency currencyhandler.CurrencyItem) (JSONCurrency, error) {
+	result := JSONCurrency{
+		ID:       currency.ID,
+		NumCode:  0,
+		CharCode: currency.CharCode,
+		Name:     currency.Name,
+		Nominal:  1,
+		Value:    decimal.FromInt(0),
+		UnitRate: decimal.FromInt(0),
+	}
+
+	var errs []error
 
 	var parseError error
 
 	if currency.NumCode != "" {
-		result.NumCode, parseError = strconv.Atoi(currency.NumCode)
+		result.NumCode, parseError = strconv.Atoi(strings.TrimSpace(currency.NumCode))
 
 		if parseError != nil {
-			panic(parseError)
+			result.NumCode = 0
+			errs = append(errs, apperrors.WrapField(apperrors.ErrTransform, index, "NumCode", parseError))
 		}
 	}
 
-	cleanValue := strings.ReplaceAll(currency.Value, ",", ".")
-	result.Value, parseError = strconv.ParseFloat(cleanValue, 64)
+	result.Value, parseError = decimal.Parse(currency.Value)
 
 	if parseError != nil {
-		panic(parseError)
+		errs = append(errs, apperrors.WrapField(apperrors.ErrTransform, index, "Value", parseError))
+	}
+
+	result.Nominal, parseError = currency.ParseNominal()
+
+	if parseError != nil {
+		result.Nominal = 1
+		errs = append(errs, apperrors.WrapField(apperrors.ErrTransform, index, "Nominal", parseError))
+	}
+
+	result.UnitRate, parseError = currency.UnitRate()
+
+	if parseError != nil && currency.VunitRate != "" {
+		errs = append(errs, apperrors.WrapField(apperrors.ErrTransform, index, "VunitRate", parseError))
+		result.UnitRate, _ = result.Value.QuoRound(decimal.FromInt(int64(result.Nominal)), decimal.DivisionScale)
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// ConvertItem applies the on-invalid policy to a single record. The
+// returned error is set only when the policy is to fail.
+func ConvertItem(index int, item currencyhandler.CurrencyItem, onInvalid string) (ItemResult, error) {
+	currency, err := processCurrency(index, item)
+	result := ItemResult{Currency: currency, Reject: nil, Defaulted: false}
+
+	switch {
+	case err == nil:
+		return result, nil
+	case onInvalid == OnInvalidSkip:
+		reject := newReject(index, item, err)
+		result.Reject = &reject
+
+		return result, nil
+	case onInvalid == OnInvalidDefault:
+		result.Defaulted = true
+
+		return result, nil
+	default:
+		return result, fmt.Errorf("%s: %w", item.CharCode, err)
+	}
+}
+
+func ConvertToJSON(data currencyhandler.CurrencyList, onInvalid string) (Conversion, error) {
+	conversion := Conversion{
+		Currencies: make([]JSONCurrency, 0, len(data.Items)),
+		Rejects:    nil,
+		Defaulted:  0,
+	}
+
+	if err := ValidatePolicy(onInvalid); err != nil {
+		return conversion, err
 	}
 
-	result.CharCode = currency.CharCode
+	for index, item := range data.Items {
+		result, err := ConvertItem(index, item, onInvalid)
+		if err != nil {
+			return conversion, err
+		}
+
+		conversion.Append(result)
+	}
 
-	return result
+	return conversion, nil
 }
 
-func ConvertToJSON(data currencyhandler.CurrencyList) []JSONCurrency {
-	converted := make([]JSONCurrency, 0, len(data.Items))
+func (c *Conversion) Append(result ItemResult) {
+	if result.Reject != nil {
+		c.Rejects = append(c.Rejects, *result.Reject)
+
+		return
+	}
+
+	if result.Defaulted {
+		c.Defaulted++
+	}
+
+	c.Currencies = append(c.Currencies, result.Currency)
+}
+
+func newReject(index int, item currencyhandler.CurrencyItem, err error) Reject {
+	reject := Reject{
+		Source:   "",
+		Index:    index,
+		CharCode: item.CharCode,
+		Field:    "",
+		Reason:   err.Error(),
+		Record:   item,
+	}
 
-	for _, item := range data.Items {
-		converted = append(converted, processCurrency(item))
+	var stageErr *apperrors.StageError
+	if errors.As(err, &stageErr) {
+		reject.Field = stageErr.Field
 	}
 
-	return converted
+	return reject
 }
@@ -0,0 +1,123 @@
+package dataprocessor_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertToJSONNominal(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		item     currencyhandler.CurrencyItem
+		nominal  int
+		value    string
+		unitRate string
+	}{
+		{
+			name:     "nominal 1",
+			item:     currencyhandler.CurrencyItem{ID: "", NumCode: "840", CharCode: "USD", Nominal: "1", Name: "", Value: "90,5567", VunitRate: ""},
+			nominal:  1,
+			value:    "90.5567",
+			unitRate: "90.5567",
+		},
+		{
+			name:     "nominal 100",
+			item:     currencyhandler.CurrencyItem{ID: "", NumCode: "392", CharCode: "JPY", Nominal: "100", Name: "", Value: "60,1020", VunitRate: ""},
+			nominal:  100,
+			value:    "60.102",
+			unitRate: "0.60102",
+		},
+		{
+			name:     "missing nominal",
+			item:     currencyhandler.CurrencyItem{ID: "", NumCode: "840", CharCode: "USD", Nominal: "", Name: "", Value: "90,5", VunitRate: ""},
+			nominal:  1,
+			value:    "90.5",
+			unitRate: "90.5",
+		},
+		{
+			name:     "VunitRate wins",
+			item:     currencyhandler.CurrencyItem{ID: "", NumCode: "392", CharCode: "JPY", Nominal: "100", Name: "", Value: "60,1020", VunitRate: "0,601"},
+			nominal:  100,
+			value:    "60.102",
+			unitRate: "0.601",
+		},
+		{
+			name:     "rounded division",
+			item:     currencyhandler.CurrencyItem{ID: "", NumCode: "", CharCode: "XYZ", Nominal: "3", Name: "", Value: "1", VunitRate: ""},
+			nominal:  3,
+			value:    "1",
+			unitRate: "0.3333333333",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := currencyhandler.CurrencyList{
+				XMLName: xml.Name{Space: "", Local: "ValCurs"},
+				Date:    "18.10.2026",
+				Name:    "",
+				Base:    currencyhandler.BaseRUB,
+				Items:   []currencyhandler.CurrencyItem{test.item},
+			}
+
+			conversion, err := dataprocessor.ConvertToJSON(input, dataprocessor.OnInvalidFail)
+			require.NoError(t, err)
+			require.Len(t, conversion.Currencies, 1)
+
+			currency := conversion.Currencies[0]
+			require.Equal(t, test.nominal, currency.Nominal)
+			require.Equal(t, test.value, currency.Value.String())
+			require.Equal(t, test.unitRate, currency.UnitRate.String())
+		})
+	}
+}
+
+func TestConvertToJSONPolicies(t *testing.T) {
+	t.Parallel()
+
+	input := currencyhandler.CurrencyList{
+		XMLName: xml.Name{Space: "", Local: "ValCurs"},
+		Date:    "18.10.2026",
+		Name:    "",
+		Base:    currencyhandler.BaseRUB,
+		Items: []currencyhandler.CurrencyItem{
+			{ID: "", NumCode: "840", CharCode: "USD", Nominal: "1", Name: "", Value: "90", VunitRate: ""},
+			{ID: "", NumCode: "", CharCode: "BAD", Nominal: "x", Name: "", Value: "n/a", VunitRate: ""},
+			{ID: "", NumCode: "978", CharCode: "EUR", Nominal: "1", Name: "", Value: "100", VunitRate: ""},
+		},
+	}
+
+	_, err := dataprocessor.ConvertToJSON(input, dataprocessor.OnInvalidFail)
+	require.ErrorIs(t, err, apperrors.ErrTransform)
+	require.ErrorContains(t, err, "field Value")
+	require.ErrorContains(t, err, "field Nominal", "every broken field is reported")
+
+	skipped, err := dataprocessor.ConvertToJSON(input, dataprocessor.OnInvalidSkip)
+	require.NoError(t, err)
+	require.Len(t, skipped.Currencies, 2)
+	require.Len(t, skipped.Rejects, 1)
+	require.Equal(t, 1, skipped.Rejects[0].Index)
+	require.Equal(t, "BAD", skipped.Rejects[0].CharCode)
+	require.Equal(t, "Value", skipped.Rejects[0].Field)
+	require.Equal(t, "n/a", skipped.Rejects[0].Record.Value)
+	require.Zero(t, skipped.Defaulted)
+
+	defaulted, err := dataprocessor.ConvertToJSON(input, dataprocessor.OnInvalidDefault)
+	require.NoError(t, err)
+	require.Len(t, defaulted.Currencies, 3)
+	require.Empty(t, defaulted.Rejects)
+	require.Equal(t, 1, defaulted.Defaulted)
+	require.Equal(t, 1, defaulted.Currencies[1].Nominal)
+	require.True(t, defaulted.Currencies[1].Value.IsZero())
+
+	_, err = dataprocessor.ConvertToJSON(input, "retry")
+	require.ErrorIs(t, err, dataprocessor.ErrUnknownPolicy)
+	require.ErrorIs(t, err, apperrors.ErrConfig)
+}
@@ -0,0 +1,79 @@
+package dataprocessor
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+)
+
+// Filter keeps currencies by CharCode. A currency passes when it matches
+// the include list or pattern (if any is set) and matches neither exclude.
+type Filter struct {
+	Include      []string `yaml:"include"`
+	Exclude      []string `yaml:"exclude"`
+	IncludeRegex string   `yaml:"include-regex"`
+	ExcludeRegex string   `yaml:"exclude-regex"`
+}
+
+type compiledFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+func (f Filter) compile() (compiledFilter, error) {
+	compiled := compiledFilter{include: nil, exclude: nil}
+
+	var err error
+
+	if f.IncludeRegex != "" {
+		compiled.include, err = regexp.Compile(f.IncludeRegex)
+		if err != nil {
+			return compiled, apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("filter include-regex: %w", err))
+		}
+	}
+
+	if f.ExcludeRegex != "" {
+		compiled.exclude, err = regexp.Compile(f.ExcludeRegex)
+		if err != nil {
+			return compiled, apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("filter exclude-regex: %w", err))
+		}
+	}
+
+	return compiled, nil
+}
+
+func containsCode(codes []string, charCode string) bool {
+	return slices.ContainsFunc(codes, func(code string) bool {
+		return strings.EqualFold(strings.TrimSpace(code), charCode)
+	})
+}
+
+func (f Filter) Apply(currencies []JSONCurrency) ([]JSONCurrency, error) {
+	compiled, err := f.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	include, exclude := compiled.include, compiled.exclude
+	restricted := len(f.Include) > 0 || include != nil
+	kept := make([]JSONCurrency, 0, len(currencies))
+
+	for _, currency := range currencies {
+		code := currency.CharCode
+
+		if restricted && !containsCode(f.Include, code) && (include == nil || !include.MatchString(code)) {
+			continue
+		}
+
+		if containsCode(f.Exclude, code) || (exclude != nil && exclude.MatchString(code)) {
+			continue
+		}
+
+		kept = append(kept, currency)
+	}
+
+	return kept, nil
+}
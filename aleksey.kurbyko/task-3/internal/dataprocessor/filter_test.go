@@ -0,0 +1,64 @@
+package dataprocessor_test
+
+import (
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterApply(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name   string
+		filter dataprocessor.Filter
+		want   []string
+	}{
+		{
+			name:   "empty keeps everything",
+			filter: dataprocessor.Filter{Include: nil, Exclude: nil, IncludeRegex: "", ExcludeRegex: ""},
+			want:   []string{"USD", "JPY", "EUR", "HUF", "CNY"},
+		},
+		{
+			name:   "include list ignores case",
+			filter: dataprocessor.Filter{Include: []string{"usd", " EUR "}, Exclude: nil, IncludeRegex: "", ExcludeRegex: ""},
+			want:   []string{"USD", "EUR"},
+		},
+		{
+			name:   "include list or pattern",
+			filter: dataprocessor.Filter{Include: []string{"USD"}, Exclude: nil, IncludeRegex: "^[CH]", ExcludeRegex: ""},
+			want:   []string{"USD", "HUF", "CNY"},
+		},
+		{
+			name:   "exclude wins",
+			filter: dataprocessor.Filter{Include: []string{"USD", "EUR"}, Exclude: []string{"eur"}, IncludeRegex: "", ExcludeRegex: ""},
+			want:   []string{"USD"},
+		},
+		{
+			name:   "exclude pattern",
+			filter: dataprocessor.Filter{Include: nil, Exclude: nil, IncludeRegex: "", ExcludeRegex: "Y$"},
+			want:   []string{"USD", "EUR", "HUF"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			kept, err := test.filter.Apply(sample())
+			require.NoError(t, err)
+			require.Equal(t, test.want, codes(kept))
+		})
+	}
+}
+
+func TestFilterBadPattern(t *testing.T) {
+	t.Parallel()
+
+	_, err := dataprocessor.Filter{Include: nil, Exclude: nil, IncludeRegex: "(", ExcludeRegex: ""}.Apply(sample())
+	require.ErrorIs(t, err, apperrors.ErrConfig)
+	require.ErrorContains(t, err, "include-regex")
+
+	_, err = dataprocessor.Filter{Include: nil, Exclude: nil, IncludeRegex: "", ExcludeRegex: "["}.Apply(sample())
+	require.ErrorContains(t, err, "exclude-regex")
+}
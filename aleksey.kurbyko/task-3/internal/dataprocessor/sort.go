@@ -0,0 +1,95 @@
+package dataprocessor
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+)
+
+var (
+	ErrUnknownSortKey       = errors.New("unknown sort key")
+	ErrUnknownSortDirection = errors.New("unknown sort direction")
+)
+
+const (
+	SortKeyValue    = "value"
+	SortKeyUnitRate = "unit_rate"
+	SortKeyCharCode = "char_code"
+	SortKeyNumCode  = "num_code"
+	SortKeyNominal  = "nominal"
+
+	DirectionAsc  = "asc"
+	DirectionDesc = "desc"
+)
+
+type SortKey struct {
+	Key       string `yaml:"key"`
+	Direction string `yaml:"direction"`
+}
+
+type comparator func(first, second *JSONCurrency) int
+
+func DefaultSortKeys() []SortKey {
+	return []SortKey{{Key: SortKeyUnitRate, Direction: DirectionDesc}}
+}
+
+func SortCurrencies(currencies []JSONCurrency, keys []SortKey) error {
+	if len(keys) == 0 {
+		keys = DefaultSortKeys()
+	}
+
+	comparators := make([]comparator, 0, len(keys))
+
+	for _, key := range keys {
+		compare, err := buildComparator(key)
+		if err != nil {
+			return apperrors.Wrap(apperrors.ErrConfig, err)
+		}
+
+		comparators = append(comparators, compare)
+	}
+
+	slices.SortStableFunc(currencies, func(first, second JSONCurrency) int {
+		for _, compare := range comparators {
+			if result := compare(&first, &second); result != 0 {
+				return result
+			}
+		}
+
+		return 0
+	})
+
+	return nil
+}
+
+func buildComparator(key SortKey) (comparator, error) {
+	var compare comparator
+
+	switch strings.ToLower(key.Key) {
+	case SortKeyValue:
+		compare = func(first, second *JSONCurrency) int { return first.Value.Cmp(second.Value) }
+	case SortKeyUnitRate:
+		compare = func(first, second *JSONCurrency) int { return first.UnitRate.Cmp(second.UnitRate) }
+	case SortKeyCharCode:
+		compare = func(first, second *JSONCurrency) int { return strings.Compare(first.CharCode, second.CharCode) }
+	case SortKeyNumCode:
+		compare = func(first, second *JSONCurrency) int { return cmp.Compare(first.NumCode, second.NumCode) }
+	case SortKeyNominal:
+		compare = func(first, second *JSONCurrency) int { return cmp.Compare(first.Nominal, second.Nominal) }
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSortKey, key.Key)
+	}
+
+	switch strings.ToLower(key.Direction) {
+	case "", DirectionAsc:
+		return compare, nil
+	case DirectionDesc:
+		return func(first, second *JSONCurrency) int { return compare(second, first) }, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSortDirection, key.Direction)
+	}
+}
@@ -0,0 +1,92 @@
+package dataprocessor_test
+
+import (
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+// sample is the fixture the sort and filter tests share.
+func sample() []dataprocessor.JSONCurrency {
+	return []dataprocessor.JSONCurrency{
+		{ID: "", NumCode: 840, CharCode: "USD", Name: "", Nominal: 1, Value: decimal.New(90, 0), UnitRate: decimal.New(90, 0)},
+		{ID: "", NumCode: 392, CharCode: "JPY", Name: "", Nominal: 100, Value: decimal.New(60, 0), UnitRate: decimal.New(6, 1)},
+		{ID: "", NumCode: 978, CharCode: "EUR", Name: "", Nominal: 1, Value: decimal.New(100, 0), UnitRate: decimal.New(100, 0)},
+		{ID: "", NumCode: 348, CharCode: "HUF", Name: "", Nominal: 100, Value: decimal.New(24, 0), UnitRate: decimal.New(24, 2)},
+		{ID: "", NumCode: 156, CharCode: "CNY", Name: "", Nominal: 1, Value: decimal.New(125, 1), UnitRate: decimal.New(125, 1)},
+	}
+}
+
+func codes(currencies []dataprocessor.JSONCurrency) []string {
+	result := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		result = append(result, currency.CharCode)
+	}
+
+	return result
+}
+
+func TestSortCurrencies(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name string
+		keys []dataprocessor.SortKey
+		want []string
+	}{
+		{name: "default is unit rate descending", keys: nil, want: []string{"EUR", "USD", "CNY", "JPY", "HUF"}},
+		{
+			name: "value ascending",
+			keys: []dataprocessor.SortKey{{Key: "value", Direction: ""}},
+			want: []string{"CNY", "HUF", "JPY", "USD", "EUR"},
+		},
+		{
+			name: "char code descending",
+			keys: []dataprocessor.SortKey{{Key: "CHAR_CODE", Direction: "DESC"}},
+			want: []string{"USD", "JPY", "HUF", "EUR", "CNY"},
+		},
+		{
+			name: "num code",
+			keys: []dataprocessor.SortKey{{Key: "num_code", Direction: "asc"}},
+			want: []string{"CNY", "HUF", "JPY", "USD", "EUR"},
+		},
+		{
+			name: "nominal then char code",
+			keys: []dataprocessor.SortKey{
+				{Key: "nominal", Direction: "desc"},
+				{Key: "char_code", Direction: "asc"},
+			},
+			want: []string{"HUF", "JPY", "CNY", "EUR", "USD"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			currencies := sample()
+			require.NoError(t, dataprocessor.SortCurrencies(currencies, test.keys))
+			require.Equal(t, test.want, codes(currencies))
+		})
+	}
+}
+
+func TestSortCurrenciesStable(t *testing.T) {
+	t.Parallel()
+
+	currencies := sample()
+	require.NoError(t, dataprocessor.SortCurrencies(currencies, []dataprocessor.SortKey{{Key: "nominal", Direction: "asc"}}))
+	require.Equal(t, []string{"USD", "EUR", "CNY", "JPY", "HUF"}, codes(currencies))
+}
+
+func TestSortCurrenciesErrors(t *testing.T) {
+	t.Parallel()
+
+	err := dataprocessor.SortCurrencies(sample(), []dataprocessor.SortKey{{Key: "rate", Direction: "asc"}})
+	require.ErrorIs(t, err, dataprocessor.ErrUnknownSortKey)
+	require.ErrorIs(t, err, apperrors.ErrConfig)
+
+	err = dataprocessor.SortCurrencies(sample(), []dataprocessor.SortKey{{Key: "value", Direction: "up"}})
+	require.ErrorIs(t, err, dataprocessor.ErrUnknownSortDirection)
+}
@@ -0,0 +1,194 @@
+package decimal
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrInvalidDecimal  = errors.New("invalid decimal")
+	ErrDivisionByZero  = errors.New("division by zero")
+	ErrUnsupportedType = errors.New("unsupported decimal source")
+)
+
+const (
+	DivisionScale = 10
+	radix         = 10
+)
+
+// Decimal is an exact fixed-point number: coef * 10^-scale.
+// The zero value is 0.
+type Decimal struct {
+	coef  *big.Int
+	scale int32
+}
+
+func New(coef int64, scale int32) Decimal {
+	return Decimal{coef: big.NewInt(coef), scale: scale}
+}
+
+func FromInt(value int64) Decimal {
+	return New(value, 0)
+}
+
+func (d Decimal) coefficient() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+
+	return d.coef
+}
+
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+func (d Decimal) Sign() int {
+	return d.coefficient().Sign()
+}
+
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+func (d Decimal) rescale(scale int32) *big.Int {
+	coef := new(big.Int).Set(d.coefficient())
+	if scale > d.scale {
+		coef.Mul(coef, pow10(scale-d.scale))
+	}
+
+	return coef
+}
+
+func align(first, second Decimal) (*big.Int, *big.Int, int32) {
+	scale := max(first.scale, second.scale)
+
+	return first.rescale(scale), second.rescale(scale), scale
+}
+
+func (d Decimal) Cmp(other Decimal) int {
+	first, second, _ := align(d, other)
+
+	return first.Cmp(second)
+}
+
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+func (d Decimal) Add(other Decimal) Decimal {
+	first, second, scale := align(d, other)
+
+	return Decimal{coef: first.Add(first, second), scale: scale}
+}
+
+func (d Decimal) Sub(other Decimal) Decimal {
+	first, second, scale := align(d, other)
+
+	return Decimal{coef: first.Sub(first, second), scale: scale}
+}
+
+func (d Decimal) Mul(other Decimal) Decimal {
+	coef := new(big.Int).Mul(d.coefficient(), other.coefficient())
+
+	return Decimal{coef: coef, scale: d.scale + other.scale}
+}
+
+func (d Decimal) Neg() Decimal {
+	return Decimal{coef: new(big.Int).Neg(d.coefficient()), scale: d.scale}
+}
+
+func (d Decimal) Abs() Decimal {
+	return Decimal{coef: new(big.Int).Abs(d.coefficient()), scale: d.scale}
+}
+
+// QuoRound divides d by other and rounds the result half away from zero
+// to the given number of fractional digits.
+func (d Decimal) QuoRound(other Decimal, scale int32) (Decimal, error) {
+	if other.IsZero() {
+		return Decimal{coef: nil, scale: 0}, ErrDivisionByZero
+	}
+
+	return FromRat(new(big.Rat).Quo(d.Rat(), other.Rat()), scale), nil
+}
+
+// Round rounds half away from zero to the given number of fractional digits.
+func (d Decimal) Round(scale int32) Decimal {
+	if scale >= d.scale {
+		return d
+	}
+
+	return FromRat(d.Rat(), scale)
+}
+
+func (d Decimal) Rat() *big.Rat {
+	denominator := pow10(d.scale)
+
+	return new(big.Rat).SetFrac(d.coefficient(), denominator)
+}
+
+func FromRat(value *big.Rat, scale int32) Decimal {
+	numerator := new(big.Int).Mul(value.Num(), pow10(scale))
+	quotient, remainder := new(big.Int).QuoRem(numerator, value.Denom(), new(big.Int))
+
+	doubled := remainder.Abs(remainder).Lsh(remainder, 1)
+	if doubled.Cmp(value.Denom()) >= 0 {
+		if value.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	return Decimal{coef: quotient, scale: scale}
+}
+
+// String renders the exact value with trailing fractional zeros removed.
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.coefficient()).Text(radix)
+	sign := ""
+
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+
+	if d.scale <= 0 {
+		if d.Sign() == 0 {
+			return "0"
+		}
+
+		return sign + digits + strings.Repeat("0", int(-d.scale))
+	}
+
+	scale := int(d.scale)
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	integer := digits[:len(digits)-scale]
+	fraction := strings.TrimRight(digits[len(digits)-scale:], "0")
+
+	if fraction == "" {
+		if integer == "0" {
+			sign = ""
+		}
+
+		return sign + integer
+	}
+
+	return sign + integer + "." + fraction
+}
+
+func pow10(exponent int32) *big.Int {
+	if exponent <= 0 {
+		return big.NewInt(1)
+	}
+
+	return new(big.Int).Exp(big.NewInt(radix), big.NewInt(int64(exponent)), nil)
+}
+
+func invalid(value string) error {
+	return fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+}
@@ -0,0 +1,115 @@
+package decimal_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		input string
+		want  string
+	}{
+		{input: "90,5567", want: "90.5567"},
+		{input: "90.5567", want: "90.5567"},
+		{input: "1,234", want: "1.234"},
+		{input: "1,234,567", want: "1234567"},
+		{input: "1.234.567", want: "1234567"},
+		{input: "1.234,56", want: "1234.56"},
+		{input: "1,234.56", want: "1234.56"},
+		{input: "1 234,56", want: "1234.56"},
+		{input: "1'234.5", want: "1234.5"},
+		{input: "1_000", want: "1000"},
+		{input: "-0,5", want: "-0.5"},
+		{input: "+12.50", want: "12.5"},
+		{input: ",5", want: "0.5"},
+		{input: "  7  ", want: "7"},
+		{input: "-0", want: "0"},
+		{input: "1,", want: "1"},
+	} {
+		t.Run(test.input, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := decimal.Parse(test.input)
+			require.NoError(t, err)
+			require.Equal(t, test.want, parsed.String())
+		})
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "  ", "-", "abc", "1e5", "--1", "1,2,3", "1.2.3", "12,34,567", "1234,567.8", "1.5x"} {
+		t.Run(input, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := decimal.Parse(input)
+			require.ErrorIs(t, err, decimal.ErrInvalidDecimal)
+		})
+	}
+}
+
+func TestFromRat(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name  string
+		value *big.Rat
+		scale int32
+		want  string
+	}{
+		{name: "exact", value: big.NewRat(1, 4), scale: 2, want: "0.25"},
+		{name: "half up", value: big.NewRat(5, 1000), scale: 2, want: "0.01"},
+		{name: "below half", value: big.NewRat(4, 1000), scale: 2, want: "0"},
+		{name: "negative half", value: big.NewRat(-5, 1000), scale: 2, want: "-0.01"},
+		{name: "negative below half", value: big.NewRat(-4, 1000), scale: 2, want: "0"},
+		{name: "repeating", value: big.NewRat(2, 3), scale: 4, want: "0.6667"},
+		{name: "integer", value: big.NewRat(5, 2), scale: 0, want: "3"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, test.want, decimal.FromRat(test.value, test.scale).String())
+		})
+	}
+}
+
+func TestQuoRound(t *testing.T) {
+	t.Parallel()
+
+	quotient, err := decimal.FromInt(1).QuoRound(decimal.FromInt(3), 3)
+	require.NoError(t, err)
+	require.Equal(t, "0.333", quotient.String())
+
+	_, err = decimal.FromInt(1).QuoRound(decimal.Decimal{}, 3)
+	require.ErrorIs(t, err, decimal.ErrDivisionByZero)
+}
+
+func TestMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	value, err := decimal.Parse("90,5567")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(map[string]decimal.Decimal{"value": value, "zero": {}})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"value": 90.5567, "zero": 0}`, string(data))
+	require.Contains(t, string(data), `"value":90.5567`)
+
+	var decoded struct {
+		Number decimal.Decimal `json:"number"`
+		Text   decimal.Decimal `json:"text"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"number": 1.10, "text": "2,5"}`), &decoded))
+	require.Equal(t, "1.1", decoded.Number.String())
+	require.Equal(t, "2.5", decoded.Text.String())
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"text": "x"}`), &decoded), decimal.ErrInvalidDecimal)
+}
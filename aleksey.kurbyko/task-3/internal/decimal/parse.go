@@ -0,0 +1,128 @@
+package decimal
+
+import (
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+const groupSize = 3
+
+// Parse reads a number written with either a comma or a dot as the
+// decimal separator. When both appear, the rightmost one is the decimal
+// separator and the other groups thousands; spaces, apostrophes and
+// underscores are accepted as thousands separators too. A single comma
+// is always treated as decimal, as in CBR feeds ("90,5567").
+func Parse(value string) (Decimal, error) {
+	text := strings.TrimSpace(value)
+	if text == "" {
+		return Decimal{coef: nil, scale: 0}, invalid(value)
+	}
+
+	sign := ""
+	if text[0] == '-' || text[0] == '+' {
+		if text[0] == '-' {
+			sign = "-"
+		}
+
+		text = text[1:]
+	}
+
+	integer, fraction, ok := splitNumber(text)
+	if !ok {
+		return Decimal{coef: nil, scale: 0}, invalid(value)
+	}
+
+	coef, ok := new(big.Int).SetString(sign+integer+fraction, radix)
+	if !ok {
+		return Decimal{coef: nil, scale: 0}, invalid(value)
+	}
+
+	return Decimal{coef: coef, scale: int32(len(fraction))}, nil
+}
+
+func splitNumber(text string) (string, string, bool) {
+	decimalSeparator := detectDecimalSeparator(text)
+
+	integerPart, fraction := text, ""
+	if decimalSeparator != 0 {
+		index := strings.LastIndexByte(text, decimalSeparator)
+		integerPart, fraction = text[:index], text[index+1:]
+	}
+
+	if !allDigits(fraction) {
+		return "", "", false
+	}
+
+	integer, ok := stripGroups(integerPart)
+	if !ok || (integer == "" && fraction == "") {
+		return "", "", false
+	}
+
+	if integer == "" {
+		integer = "0"
+	}
+
+	return integer, fraction, true
+}
+
+func detectDecimalSeparator(text string) byte {
+	lastComma := strings.LastIndexByte(text, ',')
+	lastDot := strings.LastIndexByte(text, '.')
+
+	switch {
+	case lastComma >= 0 && lastDot >= 0:
+		if lastComma > lastDot {
+			return ','
+		}
+
+		return '.'
+	case lastComma >= 0:
+		if strings.Count(text, ",") == 1 {
+			return ','
+		}
+	case lastDot >= 0:
+		if strings.Count(text, ".") == 1 {
+			return '.'
+		}
+	}
+
+	return 0
+}
+
+func stripGroups(text string) (string, bool) {
+	groups := strings.FieldsFunc(text, isGroupSeparator)
+	if len(groups) == 0 {
+		return "", !strings.ContainsFunc(text, isGroupSeparator)
+	}
+
+	for index, group := range groups {
+		if !allDigits(group) || group == "" {
+			return "", false
+		}
+
+		if index > 0 && len(group) != groupSize {
+			return "", false
+		}
+	}
+
+	if len(groups) > 1 && len(groups[0]) > groupSize {
+		return "", false
+	}
+
+	return strings.Join(groups, ""), true
+}
+
+func isGroupSeparator(r rune) bool {
+	return r == ',' || r == '.' || r == '\'' || r == '_' || unicode.IsSpace(r)
+}
+
+func allDigits(text string) bool {
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
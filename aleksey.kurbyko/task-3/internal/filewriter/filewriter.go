@@ -0,0 +1,170 @@
+package filewriter
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidMode = errors.New("invalid permission mode")
+
+const (
+	DefaultDirPerm  Mode = 0o755
+	DefaultFilePerm Mode = 0o600
+	BackupSuffix         = ".bak"
+	tempPattern          = ".*.tmp"
+	octal                = 8
+	modeBits             = 32
+)
+
+// Mode is a permission set read from YAML as an octal string such as
+// "0640" or "0o640". Plain YAML integers are taken as octal digits too,
+// so 640 means 0o640.
+type Mode os.FileMode
+
+type Options struct {
+	DirPerm  Mode
+	FilePerm Mode
+	Backup   bool
+}
+
+func DefaultOptions() Options {
+	return Options{DirPerm: DefaultDirPerm, FilePerm: DefaultFilePerm, Backup: false}
+}
+
+func ParseMode(text string) (Mode, error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(text), "0o"), "0O")
+
+	value, err := strconv.ParseUint(digits, octal, modeBits)
+	if err != nil || value&^uint64(fs.ModePerm) != 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMode, text)
+	}
+
+	return Mode(value), nil
+}
+
+func (m *Mode) UnmarshalText(text []byte) error {
+	mode, err := ParseMode(string(text))
+	if err != nil {
+		return err
+	}
+
+	*m = mode
+
+	return nil
+}
+
+func (m Mode) String() string {
+	return fmt.Sprintf("%#o", uint32(m))
+}
+
+// WriteFile replaces path with data so that readers see either the old
+// content or the new one, never a truncated file. The data goes to a temp
+// file in the same directory, is synced, and is renamed over path.
+func WriteFile(path string, data []byte, options Options) error {
+	options = options.WithDefaults()
+	dir := filepath.Dir(path)
+
+	if err := os.MkdirAll(dir, os.FileMode(options.DirPerm)); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	if options.Backup {
+		if err := backup(path, options); err != nil {
+			return err
+		}
+	}
+
+	temp, err := os.CreateTemp(dir, "."+filepath.Base(path)+tempPattern)
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+
+	tempPath := temp.Name()
+
+	err = writeTemp(temp, data, os.FileMode(options.FilePerm))
+	if err == nil {
+		err = os.Rename(tempPath, path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tempPath)
+
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+
+	return syncDir(dir)
+}
+
+// WithDefaults fills unset permissions with DefaultDirPerm and
+// DefaultFilePerm.
+func (o Options) WithDefaults() Options {
+	if o.DirPerm == 0 {
+		o.DirPerm = DefaultDirPerm
+	}
+
+	if o.FilePerm == 0 {
+		o.FilePerm = DefaultFilePerm
+	}
+
+	return o
+}
+
+func writeTemp(temp *os.File, data []byte, perm os.FileMode) error {
+	_, err := temp.Write(data)
+	if err == nil {
+		err = temp.Chmod(perm)
+	}
+
+	if err == nil {
+		err = temp.Sync()
+	}
+
+	closeErr := temp.Close()
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
+
+// backup keeps the previous output next to it. It is written atomically
+// as well, so a crash never leaves path without a readable copy.
+func backup(path string, options Options) error {
+	previous, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("read previous output: %w", err)
+	}
+
+	options.Backup = false
+
+	return WriteFile(path+BackupSuffix, previous, options)
+}
+
+func syncDir(dir string) error {
+	handle, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("sync directory: %w", err)
+	}
+
+	err = handle.Sync()
+	closeErr := handle.Close()
+
+	if err != nil {
+		return fmt.Errorf("sync directory: %w", err)
+	}
+
+	if closeErr != nil {
+		return fmt.Errorf("sync directory: %w", closeErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package filewriter_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/filewriter"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseMode(t *testing.T) {
+	t.Parallel()
+
+	for text, want := range map[string]filewriter.Mode{
+		"0755":   0o755,
+		"0o640":  0o640,
+		"600":    0o600,
+		" 0O700": 0o700,
+	} {
+		mode, err := filewriter.ParseMode(text)
+		require.NoError(t, err, text)
+		require.Equal(t, want, mode, text)
+	}
+
+	for _, text := range []string{"", "rwx", "0800", "01777", "-1"} {
+		_, err := filewriter.ParseMode(text)
+		require.ErrorIs(t, err, filewriter.ErrInvalidMode, text)
+	}
+
+	require.Equal(t, "0640", filewriter.Mode(0o640).String())
+
+	var settings struct {
+		Mode filewriter.Mode `yaml:"mode"`
+	}
+
+	require.NoError(t, yaml.Unmarshal([]byte("mode: 640"), &settings))
+	require.Equal(t, filewriter.Mode(0o640), settings.Mode)
+}
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "nested", "out")
+	path := filepath.Join(dir, "rates.json")
+	options := filewriter.Options{DirPerm: 0o750, FilePerm: 0o640, Backup: false}
+
+	require.NoError(t, filewriter.WriteFile(path, []byte("first"), options))
+	require.NoError(t, filewriter.WriteFile(path, []byte("second"), options))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "second", string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+
+	info, err = os.Stat(dir)
+	require.NoError(t, err)
+	require.Zero(t, info.Mode().Perm()&^0o750, "no more than the requested directory bits")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temp files or backups are left behind")
+}
+
+func TestWriteFileBackup(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "rates.json")
+	options := filewriter.DefaultOptions()
+	options.Backup = true
+
+	require.NoError(t, filewriter.WriteFile(path, []byte("first"), options))
+	require.NoFileExists(t, path+filewriter.BackupSuffix, "nothing to back up yet")
+
+	require.NoError(t, filewriter.WriteFile(path, []byte("second"), options))
+
+	backup, err := os.ReadFile(path + filewriter.BackupSuffix)
+	require.NoError(t, err)
+	require.Equal(t, "first", string(backup))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(filewriter.DefaultFilePerm), info.Mode().Perm())
+}
+
+func TestWriteFileZeroOptions(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "rates.json")
+
+	require.NoError(t, filewriter.WriteFile(path, []byte("data"), filewriter.Options{DirPerm: 0, FilePerm: 0, Backup: false}))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(filewriter.DefaultFilePerm), info.Mode().Perm(), "zero modes fall back to the defaults")
+
+	err = filewriter.WriteFile(filepath.Join(path, "child.json"), []byte("data"), filewriter.DefaultOptions())
+	require.Error(t, err, "a file in the way of the directory")
+}
@@ -0,0 +1,233 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aleksey.kurbyko/task-3/internal/converter"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+)
+
+var (
+	ErrNotLoaded       = errors.New("rates are not loaded")
+	errMissingCurrency = errors.New("from and to are required")
+	errPrecision       = errors.New("precision must be an integer between 0 and 20")
+)
+
+const (
+	defaultPrecision = 4
+	maxPrecision     = 20
+	contentTypeJSON  = "application/json"
+	etagBytes        = 16
+)
+
+// Loader reads the current rates, typically from the configured input.
+type Loader func(ctx context.Context) (currencyhandler.CurrencyList, []dataprocessor.JSONCurrency, error)
+
+type snapshot struct {
+	date       time.Time
+	base       string
+	currencies []dataprocessor.JSONCurrency
+	byCode     map[string]dataprocessor.JSONCurrency
+	rates      *converter.Converter
+}
+
+type Server struct {
+	load    Loader
+	mu      sync.RWMutex
+	current *snapshot
+}
+
+type convertResponse struct {
+	Date   string          `json:"date"`
+	From   string          `json:"from"`
+	To     string          `json:"to"`
+	Amount decimal.Decimal `json:"amount"`
+	Result decimal.Decimal `json:"result"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func New(load Loader) *Server {
+	return &Server{load: load, mu: sync.RWMutex{}, current: nil}
+}
+
+// Reload replaces the served rates. On failure the previous rates stay
+// in place.
+func (s *Server) Reload(ctx context.Context) error {
+	list, currencies, err := s.load(ctx)
+	if err != nil {
+		return err
+	}
+
+	date, err := list.ParseDate()
+	if err != nil {
+		return err
+	}
+
+	rates, err := converter.New(list)
+	if err != nil {
+		return err
+	}
+
+	next := &snapshot{
+		date:       date,
+		base:       list.BaseCurrency(),
+		currencies: currencies,
+		byCode:     make(map[string]dataprocessor.JSONCurrency, len(currencies)),
+		rates:      rates,
+	}
+
+	for _, currency := range currencies {
+		next.byCode[strings.ToUpper(currency.CharCode)] = currency
+	}
+
+	s.mu.Lock()
+	s.current = next
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rates", s.withSnapshot(s.handleRates))
+	mux.HandleFunc("GET /rates/{charCode}", s.withSnapshot(s.handleRate))
+	mux.HandleFunc("GET /convert", s.withSnapshot(s.handleConvert))
+
+	return mux
+}
+
+func (s *Server) withSnapshot(handle func(http.ResponseWriter, *http.Request, *snapshot)) http.HandlerFunc {
+	return func(response http.ResponseWriter, request *http.Request) {
+		s.mu.RLock()
+		current := s.current
+		s.mu.RUnlock()
+
+		if current == nil {
+			writeError(response, http.StatusServiceUnavailable, ErrNotLoaded)
+
+			return
+		}
+
+		handle(response, request, current)
+	}
+}
+
+func (s *Server) handleRates(response http.ResponseWriter, request *http.Request, current *snapshot) {
+	serveJSON(response, request, current, current.currencies)
+}
+
+func (s *Server) handleRate(response http.ResponseWriter, request *http.Request, current *snapshot) {
+	charCode := request.PathValue("charCode")
+
+	currency, ok := current.byCode[strings.ToUpper(charCode)]
+	if !ok {
+		writeError(response, http.StatusNotFound, fmt.Errorf("%w: %q", converter.ErrUnknownCurrency, charCode))
+
+		return
+	}
+
+	serveJSON(response, request, current, currency)
+}
+
+func (s *Server) handleConvert(response http.ResponseWriter, request *http.Request, current *snapshot) {
+	query := request.URL.Query()
+	from, target := strings.ToUpper(query.Get("from")), strings.ToUpper(query.Get("to"))
+
+	if from == "" || target == "" {
+		writeError(response, http.StatusBadRequest, errMissingCurrency)
+
+		return
+	}
+
+	amount := big.NewRat(1, 1)
+
+	if text := query.Get("amount"); text != "" {
+		parsed, err := converter.ParseAmount(text)
+		if err != nil {
+			writeError(response, http.StatusBadRequest, err)
+
+			return
+		}
+
+		amount = parsed
+	}
+
+	precision, err := parsePrecision(query.Get("precision"))
+	if err != nil {
+		writeError(response, http.StatusBadRequest, err)
+
+		return
+	}
+
+	result, err := current.rates.Convert(from, target, amount)
+	if err != nil {
+		writeError(response, http.StatusNotFound, err)
+
+		return
+	}
+
+	serveJSON(response, request, current, convertResponse{
+		Date:   current.date.Format(time.DateOnly),
+		From:   from,
+		To:     target,
+		Amount: decimal.FromRat(amount, precision),
+		Result: decimal.FromRat(result, precision),
+	})
+}
+
+func parsePrecision(text string) (int32, error) {
+	if text == "" {
+		return defaultPrecision, nil
+	}
+
+	precision, err := strconv.Atoi(text)
+	if err != nil || precision < 0 || precision > maxPrecision {
+		return 0, fmt.Errorf("%w: %q", errPrecision, text)
+	}
+
+	return int32(precision), nil
+}
+
+// serveJSON lets http.ServeContent answer conditional requests. The ETag
+// is a hash of the body, so a reload that changes the filter, the sort
+// order or republishes the same date never yields a stale 304;
+// Last-Modified is the feed date.
+func serveJSON(response http.ResponseWriter, request *http.Request, current *snapshot, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		writeError(response, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	data = append(data, '\n')
+	sum := sha256.Sum256(data)
+
+	response.Header().Set("Content-Type", contentTypeJSON)
+	response.Header().Set("ETag", fmt.Sprintf("%q", hex.EncodeToString(sum[:etagBytes])))
+	http.ServeContent(response, request, "", current.date, bytes.NewReader(data))
+}
+
+func writeError(response http.ResponseWriter, status int, err error) {
+	response.Header().Set("Content-Type", contentTypeJSON)
+	response.WriteHeader(status)
+
+	_ = json.NewEncoder(response).Encode(errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,192 @@
+package httpapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/httpapi"
+	"github.com/stretchr/testify/require"
+)
+
+const feed = `<ValCurs Date="18.10.2026" name="Foreign Currency Market">
+  <Valute><NumCode>840</NumCode><CharCode>USD</CharCode><Nominal>1</Nominal><Name>Dollar</Name><Value>90</Value></Valute>
+  <Valute><NumCode>978</NumCode><CharCode>EUR</CharCode><Nominal>1</Nominal><Name>Euro</Name><Value>100</Value></Valute>
+</ValCurs>`
+
+var errUnavailable = errors.New("input unavailable")
+
+// source is a Loader whose feed the test can swap, like editing the
+// input file before a SIGHUP.
+type source struct {
+	mu   sync.Mutex
+	feed string
+	fail bool
+}
+
+func (s *source) set(feed string, fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.feed, s.fail = feed, fail
+}
+
+func (s *source) load(_ context.Context) (currencyhandler.CurrencyList, []dataprocessor.JSONCurrency, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fail {
+		return currencyhandler.CurrencyList{}, nil, errUnavailable
+	}
+
+	records, err := currencyhandler.Decode(strings.NewReader(s.feed), currencyhandler.FormatCBRXML, "", "test")
+	if err != nil {
+		return currencyhandler.CurrencyList{}, nil, err
+	}
+
+	list, err := currencyhandler.Collect(records)
+	if err != nil {
+		return list, nil, err
+	}
+
+	conversion, err := dataprocessor.ConvertToJSON(list, dataprocessor.OnInvalidFail)
+
+	return list, conversion.Currencies, err
+}
+
+func get(t *testing.T, handler http.Handler, target string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	request := httptest.NewRequest(http.MethodGet, target, nil)
+	for key, values := range header {
+		request.Header[key] = values
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func loaded(t *testing.T) (*httpapi.Server, *source) {
+	t.Helper()
+
+	input := &source{mu: sync.Mutex{}, feed: feed, fail: false}
+	server := httpapi.New(input.load)
+	require.NoError(t, server.Reload(context.Background()))
+
+	return server, input
+}
+
+func TestNotLoaded(t *testing.T) {
+	t.Parallel()
+
+	input := &source{mu: sync.Mutex{}, feed: "", fail: true}
+	server := httpapi.New(input.load)
+
+	require.ErrorIs(t, server.Reload(context.Background()), errUnavailable)
+
+	response := get(t, server.Handler(), "/rates", nil)
+	require.Equal(t, http.StatusServiceUnavailable, response.Code)
+	require.JSONEq(t, `{"error": "rates are not loaded"}`, response.Body.String())
+}
+
+func TestRates(t *testing.T) {
+	t.Parallel()
+
+	server, _ := loaded(t)
+	handler := server.Handler()
+
+	response := get(t, handler, "/rates", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	require.NotEmpty(t, response.Header().Get("ETag"))
+	require.Equal(t, "Sun, 18 Oct 2026 00:00:00 GMT", response.Header().Get("Last-Modified"))
+
+	var currencies []dataprocessor.JSONCurrency
+
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &currencies))
+	require.Len(t, currencies, 2)
+
+	response = get(t, handler, "/rates/usd", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Contains(t, response.Body.String(), `"char_code":"USD"`)
+
+	response = get(t, handler, "/rates/XXX", nil)
+	require.Equal(t, http.StatusNotFound, response.Code)
+	require.Contains(t, response.Body.String(), "XXX")
+}
+
+func TestConditionalRequests(t *testing.T) {
+	t.Parallel()
+
+	server, _ := loaded(t)
+	handler := server.Handler()
+
+	first := get(t, handler, "/rates", nil)
+	etag := first.Header().Get("ETag")
+
+	response := get(t, handler, "/rates", http.Header{"If-None-Match": {etag}})
+	require.Equal(t, http.StatusNotModified, response.Code)
+	require.Empty(t, response.Body.String())
+
+	response = get(t, handler, "/rates", http.Header{"If-Modified-Since": {first.Header().Get("Last-Modified")}})
+	require.Equal(t, http.StatusNotModified, response.Code)
+
+	response = get(t, handler, "/rates/USD", http.Header{"If-None-Match": {etag}})
+	require.Equal(t, http.StatusOK, response.Code, "every resource has its own ETag")
+}
+
+func TestReloadChangesETag(t *testing.T) {
+	t.Parallel()
+
+	server, input := loaded(t)
+	handler := server.Handler()
+
+	etag := get(t, handler, "/rates", nil).Header().Get("ETag")
+
+	// Same date, republished with a corrected rate.
+	input.set(strings.Replace(feed, "<Value>90</Value>", "<Value>91</Value>", 1), false)
+	require.NoError(t, server.Reload(context.Background()))
+
+	response := get(t, handler, "/rates", http.Header{"If-None-Match": {etag}})
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NotEqual(t, etag, response.Header().Get("ETag"))
+	require.Contains(t, response.Body.String(), `"value":91`)
+
+	etag = response.Header().Get("ETag")
+
+	input.set("", true)
+	require.ErrorIs(t, server.Reload(context.Background()), errUnavailable)
+
+	response = get(t, handler, "/rates", http.Header{"If-None-Match": {etag}})
+	require.Equal(t, http.StatusNotModified, response.Code, "a failed reload keeps the previous rates")
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	server, _ := loaded(t)
+	handler := server.Handler()
+
+	response := get(t, handler, "/convert?from=usd&to=EUR&amount=10&precision=2", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.JSONEq(t, `{"date": "2026-10-18", "from": "USD", "to": "EUR", "amount": 10, "result": 9}`, response.Body.String())
+
+	for target, status := range map[string]int{
+		"/convert?from=USD":                              http.StatusBadRequest,
+		"/convert?from=USD&to=EUR&amount=ten":            http.StatusBadRequest,
+		"/convert?from=USD&to=EUR&precision=21":          http.StatusBadRequest,
+		"/convert?from=USD&to=XXX":                       http.StatusNotFound,
+		"/convert?from=RUB&to=USD&amount=90&precision=0": http.StatusOK,
+	} {
+		require.Equal(t, status, get(t, handler, target, nil).Code, target)
+	}
+}
@@ -0,0 +1,73 @@
+package ratediff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+var ErrUnknownOutput = errors.New("unknown diff output format")
+
+const (
+	OutputJSON  = "json"
+	OutputTable = "table"
+
+	tablePadding = 2
+	flagMark     = "!"
+)
+
+func Write(out io.Writer, report Report, format string) error {
+	switch format {
+	case OutputJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("encode diff: %w", err)
+		}
+
+		return nil
+	case OutputTable, "":
+		return writeTable(out, report)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownOutput, format)
+	}
+}
+
+func writeTable(out io.Writer, report Report) error {
+	table := tabwriter.NewWriter(out, 0, 0, tablePadding, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintf(table, "\tCODE\tOLD\tNEW\tCHANGE\tCHANGE %%\t\n")
+
+	for _, change := range report.Changes {
+		mark := ""
+		if change.Flagged {
+			mark = flagMark
+		}
+
+		fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			mark, change.CharCode, change.OldRate, change.NewRate, change.Change, change.ChangePct)
+	}
+
+	for _, added := range report.Added {
+		fmt.Fprintf(table, "+\t%s\t\t%s\t\t\t\n", added.CharCode, added.UnitRate)
+	}
+
+	for _, removed := range report.Removed {
+		fmt.Fprintf(table, "-\t%s\t%s\t\t\t\t\n", removed.CharCode, removed.UnitRate)
+	}
+
+	if err := table.Flush(); err != nil {
+		return fmt.Errorf("write diff table: %w", err)
+	}
+
+	_, err := fmt.Fprintf(out, "added: %d, removed: %d, compared: %d, flagged (|change| >= %s%%): %d\n",
+		len(report.Added), len(report.Removed), len(report.Changes), report.Threshold, report.Flagged)
+	if err != nil {
+		return fmt.Errorf("write diff summary: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package ratediff
+
+import (
+	"sort"
+
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+)
+
+const (
+	percent  = 100
+	pctScale = 4
+)
+
+type Listing struct {
+	CharCode string          `json:"char_code"`
+	Name     string          `json:"name,omitempty"`
+	UnitRate decimal.Decimal `json:"unit_rate"`
+}
+
+type Change struct {
+	CharCode  string          `json:"char_code"`
+	Name      string          `json:"name,omitempty"`
+	OldRate   decimal.Decimal `json:"old_rate"`
+	NewRate   decimal.Decimal `json:"new_rate"`
+	Change    decimal.Decimal `json:"change"`
+	ChangePct decimal.Decimal `json:"change_pct"`
+	Flagged   bool            `json:"flagged"`
+}
+
+type Report struct {
+	OldDate   string          `json:"old_date,omitempty"`
+	NewDate   string          `json:"new_date,omitempty"`
+	Threshold decimal.Decimal `json:"threshold_pct"`
+	Added     []Listing       `json:"added"`
+	Removed   []Listing       `json:"removed"`
+	Changes   []Change        `json:"changes"`
+	Flagged   int             `json:"flagged"`
+}
+
+// Compare joins two snapshots on CharCode and compares per-unit rates, so
+// a change of Nominal between the days is not reported as a rate move.
+// A change is flagged when its absolute percentage reaches threshold;
+// a zero threshold flags nothing.
+func Compare(previous, current []dataprocessor.JSONCurrency, threshold decimal.Decimal) Report {
+	report := Report{
+		OldDate:   "",
+		NewDate:   "",
+		Threshold: threshold,
+		Added:     []Listing{},
+		Removed:   []Listing{},
+		Changes:   []Change{},
+		Flagged:   0,
+	}
+
+	oldByCode := index(previous)
+	newByCode := index(current)
+
+	for code, currency := range newByCode {
+		old, ok := oldByCode[code]
+		if !ok {
+			report.Added = append(report.Added, listing(currency))
+
+			continue
+		}
+
+		change := compareRates(old, currency, threshold)
+		if change.Flagged {
+			report.Flagged++
+		}
+
+		report.Changes = append(report.Changes, change)
+	}
+
+	for code, currency := range oldByCode {
+		if _, ok := newByCode[code]; !ok {
+			report.Removed = append(report.Removed, listing(currency))
+		}
+	}
+
+	sort.Slice(report.Added, func(i, j int) bool { return report.Added[i].CharCode < report.Added[j].CharCode })
+	sort.Slice(report.Removed, func(i, j int) bool { return report.Removed[i].CharCode < report.Removed[j].CharCode })
+	sort.Slice(report.Changes, func(i, j int) bool { return report.Changes[i].CharCode < report.Changes[j].CharCode })
+
+	return report
+}
+
+func compareRates(previous, current dataprocessor.JSONCurrency, threshold decimal.Decimal) Change {
+	change := Change{
+		CharCode:  current.CharCode,
+		Name:      current.Name,
+		OldRate:   previous.UnitRate,
+		NewRate:   current.UnitRate,
+		Change:    current.UnitRate.Sub(previous.UnitRate),
+		ChangePct: decimal.FromInt(0),
+		Flagged:   false,
+	}
+
+	if changePct, err := change.Change.Mul(decimal.FromInt(percent)).QuoRound(previous.UnitRate, pctScale); err == nil {
+		change.ChangePct = changePct
+	}
+
+	change.Flagged = threshold.Sign() > 0 && change.ChangePct.Abs().Cmp(threshold) >= 0
+
+	return change
+}
+
+func index(currencies []dataprocessor.JSONCurrency) map[string]dataprocessor.JSONCurrency {
+	byCode := make(map[string]dataprocessor.JSONCurrency, len(currencies))
+	for _, currency := range currencies {
+		byCode[currency.CharCode] = currency
+	}
+
+	return byCode
+}
+
+func listing(currency dataprocessor.JSONCurrency) Listing {
+	return Listing{CharCode: currency.CharCode, Name: currency.Name, UnitRate: currency.UnitRate}
+}
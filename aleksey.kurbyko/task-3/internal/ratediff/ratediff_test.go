@@ -0,0 +1,114 @@
+package ratediff_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+	"github.com/aleksey.kurbyko/task-3/internal/ratediff"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	previous := []dataprocessor.JSONCurrency{
+		{ID: "", NumCode: 0, CharCode: "USD", Name: "", Nominal: 1, Value: decimal.New(90, 0), UnitRate: decimal.New(90, 0)},
+		{ID: "", NumCode: 0, CharCode: "JPY", Name: "", Nominal: 100, Value: decimal.New(60, 0), UnitRate: decimal.New(6, 1)},
+		{ID: "", NumCode: 0, CharCode: "GBP", Name: "", Nominal: 1, Value: decimal.New(110, 0), UnitRate: decimal.New(110, 0)},
+		{ID: "", NumCode: 0, CharCode: "EUR", Name: "", Nominal: 1, Value: decimal.New(100, 0), UnitRate: decimal.New(100, 0)},
+	}
+	current := []dataprocessor.JSONCurrency{
+		{ID: "", NumCode: 0, CharCode: "EUR", Name: "", Nominal: 1, Value: decimal.New(101, 0), UnitRate: decimal.New(101, 0)},
+		{ID: "", NumCode: 0, CharCode: "USD", Name: "", Nominal: 1, Value: decimal.New(8991, 2), UnitRate: decimal.New(8991, 2)},
+		{ID: "", NumCode: 0, CharCode: "JPY", Name: "", Nominal: 10, Value: decimal.New(6, 0), UnitRate: decimal.New(6, 1)},
+		{ID: "", NumCode: 0, CharCode: "CNY", Name: "", Nominal: 1, Value: decimal.New(125, 1), UnitRate: decimal.New(125, 1)},
+	}
+
+	report := ratediff.Compare(previous, current, decimal.New(5, 1))
+
+	require.Equal(t, []string{"CNY"}, codes(report.Added))
+	require.Equal(t, []string{"GBP"}, codes(report.Removed))
+	require.Len(t, report.Changes, 3)
+	require.Equal(t, 1, report.Flagged)
+
+	eur, jpy, usd := report.Changes[0], report.Changes[1], report.Changes[2]
+
+	require.Equal(t, "EUR", eur.CharCode)
+	require.Equal(t, "1", eur.Change.String())
+	require.Equal(t, "1", eur.ChangePct.String())
+	require.True(t, eur.Flagged)
+
+	require.Equal(t, "JPY", jpy.CharCode, "a nominal change is not a rate move")
+	require.True(t, jpy.Change.IsZero())
+	require.False(t, jpy.Flagged)
+
+	require.Equal(t, "USD", usd.CharCode)
+	require.Equal(t, "-0.09", usd.Change.String())
+	require.Equal(t, "-0.1", usd.ChangePct.String())
+	require.False(t, usd.Flagged)
+}
+
+func TestCompareZeroThreshold(t *testing.T) {
+	t.Parallel()
+
+	report := ratediff.Compare(
+		[]dataprocessor.JSONCurrency{{ID: "", NumCode: 0, CharCode: "USD", Name: "", Nominal: 1, Value: decimal.New(90, 0), UnitRate: decimal.New(90, 0)}},
+		[]dataprocessor.JSONCurrency{{ID: "", NumCode: 0, CharCode: "USD", Name: "", Nominal: 1, Value: decimal.New(180, 0), UnitRate: decimal.New(180, 0)}},
+		decimal.FromInt(0),
+	)
+
+	require.Equal(t, "100", report.Changes[0].ChangePct.String())
+	require.False(t, report.Changes[0].Flagged)
+	require.Zero(t, report.Flagged)
+	require.Empty(t, report.Added)
+	require.Empty(t, report.Removed)
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	report := ratediff.Compare(
+		[]dataprocessor.JSONCurrency{
+			{ID: "", NumCode: 0, CharCode: "USD", Name: "", Nominal: 1, Value: decimal.New(90, 0), UnitRate: decimal.New(90, 0)},
+			{ID: "", NumCode: 0, CharCode: "GBP", Name: "", Nominal: 1, Value: decimal.New(110, 0), UnitRate: decimal.New(110, 0)},
+		},
+		[]dataprocessor.JSONCurrency{
+			{ID: "", NumCode: 0, CharCode: "USD", Name: "", Nominal: 1, Value: decimal.New(99, 0), UnitRate: decimal.New(99, 0)},
+			{ID: "", NumCode: 0, CharCode: "CNY", Name: "", Nominal: 1, Value: decimal.New(12, 0), UnitRate: decimal.New(12, 0)},
+		},
+		decimal.FromInt(1),
+	)
+
+	var table bytes.Buffer
+
+	require.NoError(t, ratediff.Write(&table, report, ratediff.OutputTable))
+	require.Contains(t, table.String(), "CODE")
+	require.Regexp(t, `!\s+USD\s+90\s+99\s+9\s+10`, table.String())
+	require.Regexp(t, `\+\s+CNY\s+12`, table.String())
+	require.Regexp(t, `-\s+GBP\s+110`, table.String())
+	require.Contains(t, table.String(), "added: 1, removed: 1, compared: 1, flagged (|change| >= 1%): 1")
+
+	var encoded bytes.Buffer
+
+	require.NoError(t, ratediff.Write(&encoded, report, ratediff.OutputJSON))
+
+	var decoded map[string]any
+
+	require.NoError(t, json.Unmarshal(encoded.Bytes(), &decoded))
+	require.InDelta(t, 1.0, decoded["flagged"], 0)
+	require.Len(t, decoded["changes"], 1)
+
+	require.ErrorIs(t, ratediff.Write(&encoded, report, "html"), ratediff.ErrUnknownOutput)
+}
+
+func codes(listings []ratediff.Listing) []string {
+	result := make([]string, 0, len(listings))
+	for _, listing := range listings {
+		result = append(result, listing.CharCode)
+	}
+
+	return result
+}
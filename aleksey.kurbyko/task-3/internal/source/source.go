@@ -0,0 +1,217 @@
+package source
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBadStatus      = errors.New("unexpected http status")
+	ErrMemberNotFound = errors.New("archive member not found")
+	ErrAmbiguousZip   = errors.New("zip archive holds several files, name one with #member")
+)
+
+const (
+	Stdin         = "-"
+	memberMark    = "#"
+	httpTimeout   = 30 * time.Second
+	userAgent     = "task-3-currency-converter"
+	gzipExtension = ".gz"
+	zipExtension  = ".zip"
+)
+
+// Open returns the raw bytes behind location, which may be "-" for
+// stdin, an http(s) URL, a local path, a ".gz" file or a zip archive
+// member written as "archive.zip#member.xml".
+func Open(ctx context.Context, location string) (io.ReadCloser, error) {
+	name, member := splitMember(location)
+
+	if isZip(name) {
+		return openZipMember(ctx, name, member)
+	}
+
+	reader, err := openRaw(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasSuffix(strings.ToLower(trimQuery(name)), gzipExtension) {
+		return reader, nil
+	}
+
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		_ = reader.Close()
+
+		return nil, fmt.Errorf("gzip %s: %w", name, err)
+	}
+
+	return readCloser{Reader: gzipReader, closers: []io.Closer{gzipReader, reader}}, nil
+}
+
+// IsSingle reports whether location always names exactly one document,
+// so it must not be expanded as a directory or glob.
+func IsSingle(location string) bool {
+	name, member := splitMember(location)
+
+	return location == Stdin || IsURL(location) || (isZip(name) && member != "")
+}
+
+// LocalPath returns the file that backs location, or false when the data
+// comes from stdin or the network.
+func LocalPath(location string) (string, bool) {
+	if location == Stdin || IsURL(location) {
+		return "", false
+	}
+
+	name, _ := splitMember(location)
+
+	return name, true
+}
+
+func IsURL(location string) bool {
+	lower := strings.ToLower(location)
+
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+func openRaw(ctx context.Context, location string) (io.ReadCloser, error) {
+	switch {
+	case location == Stdin:
+		return io.NopCloser(os.Stdin), nil
+	case IsURL(location):
+		return openURL(ctx, location)
+	default:
+		file, err := os.Open(location)
+		if err != nil {
+			return nil, fmt.Errorf("open: %w", err)
+		}
+
+		return file, nil
+	}
+}
+
+func openURL(ctx context.Context, location string) (io.ReadCloser, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+
+	request.Header.Set("User-Agent", userAgent)
+
+	client := &http.Client{Timeout: httpTimeout}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", location, err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+
+		return nil, fmt.Errorf("%w: %s: %s", ErrBadStatus, location, response.Status)
+	}
+
+	return response.Body, nil
+}
+
+func openZipMember(ctx context.Context, name string, member string) (io.ReadCloser, error) {
+	reader, err := openRaw(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(reader)
+	_ = reader.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("zip %s: %w", name, err)
+	}
+
+	file, err := findMember(archive, member)
+	if err != nil {
+		return nil, fmt.Errorf("zip %s: %w", name, err)
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("zip %s: open %s: %w", name, file.Name, err)
+	}
+
+	return content, nil
+}
+
+func findMember(archive *zip.Reader, member string) (*zip.File, error) {
+	var files []*zip.File
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		if member == "" || file.Name == member || path.Base(file.Name) == member {
+			files = append(files, file)
+		}
+	}
+
+	switch {
+	case len(files) == 0:
+		return nil, fmt.Errorf("%w: %q", ErrMemberNotFound, member)
+	case len(files) > 1 && member == "":
+		return nil, ErrAmbiguousZip
+	default:
+		return files[0], nil
+	}
+}
+
+func splitMember(location string) (string, string) {
+	index := strings.LastIndex(location, memberMark)
+	if index < 0 || !isZip(location[:index]) {
+		return location, ""
+	}
+
+	return location[:index], location[index+len(memberMark):]
+}
+
+func isZip(name string) bool {
+	return strings.HasSuffix(strings.ToLower(trimQuery(name)), zipExtension)
+}
+
+func trimQuery(location string) string {
+	if index := strings.IndexAny(location, "?#"); index >= 0 && IsURL(location) {
+		return location[:index]
+	}
+
+	return location
+}
+
+type readCloser struct {
+	io.Reader
+
+	closers []io.Closer
+}
+
+func (r readCloser) Close() error {
+	var errs []error
+
+	for _, closer := range r.closers {
+		errs = append(errs, closer.Close())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,144 @@
+package source_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/source"
+	"github.com/stretchr/testify/require"
+)
+
+const document = `<?xml version="1.0" encoding="UTF-8"?><ValCurs Date="18.10.2026"></ValCurs>`
+
+func readAll(t *testing.T, location string) string {
+	t.Helper()
+
+	reader, err := source.Open(context.Background(), location)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, reader.Close()) }()
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func gzipped(t *testing.T, content string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+	_, err := writer.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return buffer.Bytes()
+}
+
+func writeZip(t *testing.T, members map[string]string) string {
+	t.Helper()
+
+	archivePath := filepath.Join(t.TempDir(), "daily.zip")
+
+	file, err := os.Create(archivePath)
+	require.NoError(t, err)
+
+	writer := zip.NewWriter(file)
+
+	for name, content := range members {
+		member, err := writer.Create(name)
+		require.NoError(t, err)
+
+		_, err = member.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, writer.Close())
+	require.NoError(t, file.Close())
+
+	return archivePath
+}
+
+func TestOpenURL(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		switch request.URL.Path {
+		case "/scripts/XML_daily.asp":
+			_, _ = io.WriteString(writer, document)
+		case "/archive/daily.xml.gz":
+			_, _ = writer.Write(gzipped(t, document))
+		default:
+			http.NotFound(writer, request)
+		}
+	}))
+	defer server.Close()
+
+	require.Equal(t, document, readAll(t, server.URL+"/scripts/XML_daily.asp?date_req=18/10/2026"))
+	require.Equal(t, document, readAll(t, server.URL+"/archive/daily.xml.gz"))
+
+	_, err := source.Open(context.Background(), server.URL+"/missing.xml")
+	require.ErrorIs(t, err, source.ErrBadStatus)
+}
+
+func TestOpenURLCanceled(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(writer, document)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := source.Open(ctx, server.URL)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestOpenGzipFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "daily.xml.gz")
+	require.NoError(t, os.WriteFile(path, gzipped(t, document), 0o600))
+
+	require.Equal(t, document, readAll(t, path))
+}
+
+func TestOpenZipMember(t *testing.T) {
+	t.Parallel()
+
+	single := writeZip(t, map[string]string{"daily.xml": document})
+	require.Equal(t, document, readAll(t, single))
+	require.Equal(t, document, readAll(t, single+"#daily.xml"))
+
+	several := writeZip(t, map[string]string{"2026/17.xml": "first", "2026/18.xml": document})
+	require.Equal(t, document, readAll(t, several+"#18.xml"))
+	require.Equal(t, "first", readAll(t, several+"#2026/17.xml"))
+
+	_, err := source.Open(context.Background(), several)
+	require.ErrorIs(t, err, source.ErrAmbiguousZip)
+
+	_, err = source.Open(context.Background(), several+"#19.xml")
+	require.ErrorIs(t, err, source.ErrMemberNotFound)
+}
+
+func TestIsSingle(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, source.IsSingle(source.Stdin))
+	require.True(t, source.IsSingle("https://www.cbr.ru/scripts/XML_daily.asp?date_req=*"))
+	require.True(t, source.IsSingle("archive.zip#daily.xml"))
+	require.False(t, source.IsSingle("archive.zip"))
+	require.False(t, source.IsSingle("daily/*.xml"))
+}
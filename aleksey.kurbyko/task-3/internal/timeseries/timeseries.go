@@ -0,0 +1,143 @@
+package timeseries
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+)
+
+const (
+	DateLayout = "2006-01-02"
+	percent    = 100
+	pctScale   = 4
+)
+
+type Point struct {
+	Date      string          `json:"date"       xml:"date,attr"  yaml:"date"`
+	UnitRate  decimal.Decimal `json:"unit_rate"  xml:"unit_rate"  yaml:"unit_rate"`
+	Change    decimal.Decimal `json:"change"     xml:"change"     yaml:"change"`
+	ChangePct decimal.Decimal `json:"change_pct" xml:"change_pct" yaml:"change_pct"`
+}
+
+type Summary struct {
+	CharCode string          `json:"char_code"      xml:"char_code,attr" yaml:"char_code"`
+	NumCode  int             `json:"num_code"       xml:"num_code"       yaml:"num_code"`
+	Name     string          `json:"name,omitempty" xml:"name,omitempty" yaml:"name,omitempty"`
+	From     string          `json:"from"           xml:"from"           yaml:"from"`
+	To       string          `json:"to"             xml:"to"             yaml:"to"`
+	Min      decimal.Decimal `json:"min"            xml:"min"            yaml:"min"`
+	Max      decimal.Decimal `json:"max"            xml:"max"            yaml:"max"`
+	Mean     decimal.Decimal `json:"mean"           xml:"mean"           yaml:"mean"`
+	Points   []Point         `json:"points"         xml:"point"          yaml:"points"`
+}
+
+type observation struct {
+	date     time.Time
+	currency dataprocessor.JSONCurrency
+}
+
+type Series struct {
+	observations map[string]map[time.Time]dataprocessor.JSONCurrency
+}
+
+func New() *Series {
+	return &Series{
+		observations: make(map[string]map[time.Time]dataprocessor.JSONCurrency),
+	}
+}
+
+// Observe records one currency of a daily snapshot. A later observation
+// for the same date replaces the earlier one.
+func (s *Series) Observe(date time.Time, currency dataprocessor.JSONCurrency) {
+	byDate, ok := s.observations[currency.CharCode]
+	if !ok {
+		byDate = make(map[time.Time]dataprocessor.JSONCurrency)
+		s.observations[currency.CharCode] = byDate
+	}
+
+	byDate[date] = currency
+}
+
+func (s *Series) Summaries() []Summary {
+	summaries := make([]Summary, 0, len(s.observations))
+
+	for _, byDate := range s.observations {
+		summaries = append(summaries, summarize(sortedObservations(byDate)))
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].CharCode < summaries[j].CharCode
+	})
+
+	return summaries
+}
+
+func sortedObservations(byDate map[time.Time]dataprocessor.JSONCurrency) []observation {
+	observations := make([]observation, 0, len(byDate))
+
+	for date, currency := range byDate {
+		observations = append(observations, observation{date: date, currency: currency})
+	}
+
+	sort.Slice(observations, func(i, j int) bool {
+		return observations[i].date.Before(observations[j].date)
+	})
+
+	return observations
+}
+
+func summarize(observations []observation) Summary {
+	first := observations[0]
+	last := observations[len(observations)-1]
+
+	summary := Summary{
+		CharCode: last.currency.CharCode,
+		NumCode:  last.currency.NumCode,
+		Name:     last.currency.Name,
+		From:     first.date.Format(DateLayout),
+		To:       last.date.Format(DateLayout),
+		Min:      first.currency.UnitRate,
+		Max:      first.currency.UnitRate,
+		Mean:     decimal.FromInt(0),
+		Points:   make([]Point, 0, len(observations)),
+	}
+
+	total := decimal.FromInt(0)
+
+	for index, current := range observations {
+		rate := current.currency.UnitRate
+		point := Point{
+			Date:      current.date.Format(DateLayout),
+			UnitRate:  rate,
+			Change:    decimal.FromInt(0),
+			ChangePct: decimal.FromInt(0),
+		}
+
+		if index > 0 {
+			previous := observations[index-1].currency.UnitRate
+			point.Change = rate.Sub(previous)
+
+			if changePct, err := point.Change.Mul(decimal.FromInt(percent)).QuoRound(previous, pctScale); err == nil {
+				point.ChangePct = changePct
+			}
+		}
+
+		if rate.Cmp(summary.Min) < 0 {
+			summary.Min = rate
+		}
+
+		if rate.Cmp(summary.Max) > 0 {
+			summary.Max = rate
+		}
+
+		total = total.Add(rate)
+
+		summary.Points = append(summary.Points, point)
+	}
+
+	summary.Mean, _ = total.QuoRound(decimal.FromInt(int64(len(observations))), decimal.DivisionScale)
+
+	return summary
+}
@@ -0,0 +1,90 @@
+package timeseries_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+	"github.com/aleksey.kurbyko/task-3/internal/timeseries"
+	"github.com/stretchr/testify/require"
+)
+
+func day(value string) time.Time {
+	date, err := time.Parse(timeseries.DateLayout, value)
+	if err != nil {
+		panic(err)
+	}
+
+	return date
+}
+
+func currency(charCode, rate string) dataprocessor.JSONCurrency {
+	unitRate, err := decimal.Parse(rate)
+	if err != nil {
+		panic(err)
+	}
+
+	return dataprocessor.JSONCurrency{
+		ID:       "",
+		NumCode:  840,
+		CharCode: charCode,
+		Name:     charCode + " name",
+		Nominal:  1,
+		Value:    unitRate,
+		UnitRate: unitRate,
+	}
+}
+
+func TestSummaries(t *testing.T) {
+	t.Parallel()
+
+	series := timeseries.New()
+	series.Observe(day("2026-10-03"), currency("USD", "90"))
+	series.Observe(day("2026-10-01"), currency("USD", "80"))
+	series.Observe(day("2026-10-02"), currency("USD", "100"))
+	series.Observe(day("2026-10-01"), currency("EUR", "95,5"))
+
+	summaries := series.Summaries()
+	require.Len(t, summaries, 2)
+	require.Equal(t, "EUR", summaries[0].CharCode)
+	require.Equal(t, "USD", summaries[1].CharCode)
+
+	usd := summaries[1]
+	require.Equal(t, "2026-10-01", usd.From)
+	require.Equal(t, "2026-10-03", usd.To)
+	require.Equal(t, "80", usd.Min.String())
+	require.Equal(t, "100", usd.Max.String())
+	require.Equal(t, "90", usd.Mean.String())
+
+	require.Len(t, usd.Points, 3)
+	require.Equal(t, "2026-10-01", usd.Points[0].Date)
+	require.Equal(t, "0", usd.Points[0].Change.String())
+	require.Equal(t, "20", usd.Points[1].Change.String())
+	require.Equal(t, "25", usd.Points[1].ChangePct.String())
+	require.Equal(t, "-10", usd.Points[2].Change.String())
+	require.Equal(t, "-10", usd.Points[2].ChangePct.String())
+
+	eur := summaries[0]
+	require.Len(t, eur.Points, 1)
+	require.Equal(t, "95.5", eur.Mean.String())
+}
+
+func TestObserveReplacesSameDate(t *testing.T) {
+	t.Parallel()
+
+	series := timeseries.New()
+	series.Observe(day("2026-10-01"), currency("USD", "80"))
+	series.Observe(day("2026-10-01"), currency("USD", "81,25"))
+
+	summaries := series.Summaries()
+	require.Len(t, summaries, 1)
+	require.Len(t, summaries[0].Points, 1)
+	require.Equal(t, "81.25", summaries[0].Points[0].UnitRate.String())
+}
+
+func TestSummariesEmpty(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, timeseries.New().Summaries())
+}
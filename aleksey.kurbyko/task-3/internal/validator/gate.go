@@ -0,0 +1,180 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+)
+
+// RejectFunc receives a record the skip policy drops, with its violations.
+type RejectFunc func(index int, item currencyhandler.CurrencyItem, violations []Violation)
+
+// Options select how an input is decoded and checked.
+type Options struct {
+	Format    string
+	Encoding  string
+	Rules     Rules
+	OnInvalid string
+	// Reject is told about records dropped by the skip policy; may be nil.
+	Reject RejectFunc
+}
+
+// Open is the decode path every command reads input through: records
+// come out of it already validated.
+func Open(ctx context.Context, location string, options Options) (*Gate, error) {
+	records, err := currencyhandler.Open(ctx, location, options.Format, options.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	gate, err := NewGate(records, location, options)
+	if err != nil {
+		_ = records.Close()
+
+		return nil, err
+	}
+
+	return gate, nil
+}
+
+// Decode is Open for a reader the caller keeps ownership of.
+func Decode(reader io.Reader, options Options) (*Gate, error) {
+	records, err := currencyhandler.Decode(reader, options.Format, options.Encoding, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewGate(records, "", options)
+}
+
+// DecodeFile reads a whole validated input into a list.
+func DecodeFile(ctx context.Context, location string, options Options) (currencyhandler.CurrencyList, error) {
+	gate, err := Open(ctx, location, options)
+	if err != nil {
+		return currencyhandler.CurrencyList{}, err
+	}
+
+	defer func() { _ = gate.Close() }()
+
+	return currencyhandler.Collect(gate)
+}
+
+// Gate applies the validation stage to a record stream. Record
+// violations follow the on-invalid policy: fail collects them and fails
+// at the end with the full list, skip drops the record and reports it to
+// Reject, default lets it through to be defaulted. Document-level
+// violations (too few records) always fail.
+type Gate struct {
+	currencyhandler.Records
+
+	validator  *Validator
+	options    Options
+	source     string
+	started    bool
+	finished   bool
+	date       string
+	violations []Violation
+	err        error
+}
+
+func NewGate(records currencyhandler.Records, source string, options Options) (*Gate, error) {
+	if err := dataprocessor.ValidatePolicy(options.OnInvalid); err != nil {
+		return nil, err
+	}
+
+	checker, err := New(options.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Gate{
+		Records:    records,
+		validator:  checker,
+		options:    options,
+		source:     source,
+		started:    false,
+		finished:   false,
+		date:       "",
+		violations: nil,
+		err:        nil,
+	}, nil
+}
+
+func (g *Gate) Next() bool {
+	for g.Records.Next() {
+		g.begin(g.Records.Date())
+
+		if g.admit(g.Records.Index(), g.Records.Item()) {
+			return true
+		}
+	}
+
+	if !g.finished && g.Records.Err() == nil {
+		g.finished = true
+		g.err = g.finish()
+	}
+
+	return false
+}
+
+func (g *Gate) Err() error {
+	if err := g.Records.Err(); err != nil {
+		return err
+	}
+
+	return g.err
+}
+
+func (g *Gate) begin(date string) {
+	if g.started && date == g.date {
+		return
+	}
+
+	if g.started {
+		g.violations = append(g.violations, g.validator.Finish()...)
+	}
+
+	g.started = true
+	g.date = date
+	g.validator.Reset(date)
+}
+
+func (g *Gate) admit(index int, item currencyhandler.CurrencyItem) bool {
+	violations := g.validator.Check(index, item)
+	if len(violations) == 0 {
+		return true
+	}
+
+	switch g.options.OnInvalid {
+	case dataprocessor.OnInvalidSkip:
+		if g.options.Reject != nil {
+			g.options.Reject(index, item, violations)
+		}
+
+		return false
+	case dataprocessor.OnInvalidDefault:
+		return true
+	default:
+		g.violations = append(g.violations, violations...)
+
+		return false
+	}
+}
+
+func (g *Gate) finish() error {
+	if !g.started {
+		g.begin("")
+	}
+
+	g.violations = append(g.violations, g.validator.Finish()...)
+
+	err := AsError(g.violations)
+	if err != nil && g.source != "" {
+		return fmt.Errorf("%s: %w", g.source, err)
+	}
+
+	return err
+}
@@ -0,0 +1,87 @@
+package validator_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/validator"
+	"github.com/stretchr/testify/require"
+)
+
+const feed = `<ValCurs Date="18.10.2026" name="Foreign Currency Market">
+  <Valute><NumCode>840</NumCode><CharCode>USD</CharCode><Nominal>1</Nominal><Name>Dollar</Name><Value>90,5</Value></Valute>
+  <Valute><NumCode>999</NumCode><CharCode>ZZZ</CharCode><Nominal>1</Nominal><Name>Bogus</Name><Value>1</Value></Valute>
+  <Valute><NumCode>978</NumCode><CharCode>EUR</CharCode><Nominal>1</Nominal><Name>Euro</Name><Value>99,1</Value></Valute>
+</ValCurs>`
+
+func options(onInvalid string) validator.Options {
+	return validator.Options{
+		Format:    currencyhandler.FormatCBRXML,
+		Encoding:  "",
+		Rules:     noRules(),
+		OnInvalid: onInvalid,
+		Reject:    nil,
+	}
+}
+
+func charCodes(t *testing.T, onInvalid string, reject validator.RejectFunc) ([]string, error) {
+	t.Helper()
+
+	settings := options(onInvalid)
+	settings.Reject = reject
+
+	gate, err := validator.Decode(strings.NewReader(feed), settings)
+	require.NoError(t, err)
+
+	var codes []string
+	for gate.Next() {
+		codes = append(codes, gate.Item().CharCode)
+	}
+
+	return codes, gate.Err()
+}
+
+func TestGatePolicies(t *testing.T) {
+	t.Parallel()
+
+	codes, err := charCodes(t, dataprocessor.OnInvalidFail, nil)
+	require.Equal(t, []string{"USD", "EUR"}, codes)
+	require.Len(t, violations(t, err), 1)
+
+	var rejected []int
+
+	codes, err = charCodes(t, dataprocessor.OnInvalidSkip, func(index int, _ currencyhandler.CurrencyItem, _ []validator.Violation) {
+		rejected = append(rejected, index)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"USD", "EUR"}, codes)
+	require.Equal(t, []int{1}, rejected)
+
+	codes, err = charCodes(t, dataprocessor.OnInvalidDefault, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"USD", "ZZZ", "EUR"}, codes)
+
+	_, err = validator.Decode(strings.NewReader(feed), options("retry"))
+	require.ErrorIs(t, err, apperrors.ErrConfig)
+}
+
+func TestDecodeFileMisspelledValute(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "daily.xml")
+	require.NoError(t, os.WriteFile(path, []byte(strings.ReplaceAll(feed, "Valute", "Valuta")), 0o600))
+
+	_, err := validator.DecodeFile(context.Background(), path, options(dataprocessor.OnInvalidSkip))
+	require.ErrorIs(t, err, apperrors.ErrValidate)
+	require.ErrorContains(t, err, path)
+
+	list, err := validator.DecodeFile(context.Background(), path+"-missing", options(""))
+	require.ErrorIs(t, err, apperrors.ErrInput)
+	require.Empty(t, list.Items)
+}
@@ -0,0 +1,314 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+)
+
+var (
+	ErrUnknownField = errors.New("unknown required field")
+	ErrViolations   = errors.New("input violates schema")
+)
+
+const (
+	FieldNumCode   = "NumCode"
+	FieldCharCode  = "CharCode"
+	FieldNominal   = "Nominal"
+	FieldName      = "Name"
+	FieldValue     = "Value"
+	FieldVunitRate = "VunitRate"
+
+	DefaultMinRecords = 1
+	numCodeLength     = 3
+	charCodeLength    = 3
+)
+
+// Rules configure validation. Without Required only CharCode and Value
+// must be present; listing NumCode also checks it as an ISO 4217 numeric
+// code.
+type Rules struct {
+	MinRecords  *int     `yaml:"min-records"`
+	Required    []string `yaml:"required"`
+	AllowNonISO bool     `yaml:"allow-non-iso"`
+}
+
+type Violation struct {
+	Index    int    `json:"index"`
+	CharCode string `json:"char_code,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (v Violation) String() string {
+	var builder strings.Builder
+
+	if v.Index != apperrors.NoIndex {
+		fmt.Fprintf(&builder, "valute #%d", v.Index)
+
+		if v.CharCode != "" {
+			fmt.Fprintf(&builder, " (%s)", v.CharCode)
+		}
+
+		builder.WriteString(": ")
+	}
+
+	if v.Field != "" {
+		builder.WriteString(v.Field + ": ")
+	}
+
+	builder.WriteString(v.Message)
+
+	return builder.String()
+}
+
+// Error carries every violation found in a document.
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	lines := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		lines = append(lines, violation.String())
+	}
+
+	return fmt.Sprintf("%s: %d violation(s):\n  %s", ErrViolations, len(e.Violations), strings.Join(lines, "\n  "))
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{apperrors.ErrValidate, ErrViolations}
+}
+
+// Validator checks records one at a time so that it also fits the
+// streaming decoders. Call Reset at every document (ValCurs block)
+// boundary and Finish at its end.
+type Validator struct {
+	minRecords  int
+	required    []string
+	checkNum    bool
+	checkISO    bool
+	iso         map[string]string
+	count       int
+	charCodes   map[string]int
+	numCodes    map[string]int
+	documentTag string
+}
+
+func New(rules Rules) (*Validator, error) {
+	minRecords := DefaultMinRecords
+	if rules.MinRecords != nil {
+		minRecords = *rules.MinRecords
+	}
+
+	for _, field := range rules.Required {
+		if !knownField(field) {
+			return nil, apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("%w: %q", ErrUnknownField, field))
+		}
+	}
+
+	validator := &Validator{
+		minRecords:  minRecords,
+		required:    rules.Required,
+		checkNum:    slices.Contains(rules.Required, FieldNumCode),
+		checkISO:    !rules.AllowNonISO,
+		iso:         iso4217(),
+		count:       0,
+		charCodes:   nil,
+		numCodes:    nil,
+		documentTag: "",
+	}
+	validator.Reset("")
+
+	return validator, nil
+}
+
+// Reset starts a new document. tag, usually its date, prefixes
+// document-level messages.
+func (v *Validator) Reset(tag string) {
+	v.documentTag = tag
+	v.count = 0
+	v.charCodes = make(map[string]int)
+	v.numCodes = make(map[string]int)
+}
+
+func (v *Validator) requiredFields() []string {
+	if len(v.required) > 0 {
+		return v.required
+	}
+
+	return []string{FieldCharCode, FieldValue}
+}
+
+func (v *Validator) Check(index int, item currencyhandler.CurrencyItem) []Violation {
+	v.count++
+
+	var violations []Violation
+
+	report := func(field, format string, args ...any) {
+		violations = append(violations, Violation{
+			Index:    index,
+			CharCode: item.CharCode,
+			Field:    field,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, field := range v.requiredFields() {
+		if strings.TrimSpace(fieldValue(item, field)) == "" {
+			report(field, "required element is missing or empty")
+		}
+	}
+
+	v.checkCodes(index, item, report)
+	v.checkNumbers(item, report)
+
+	return violations
+}
+
+func (v *Validator) checkCodes(index int, item currencyhandler.CurrencyItem, report func(string, string, ...any)) {
+	charCode := strings.TrimSpace(item.CharCode)
+
+	numCode := ""
+	if v.checkNum {
+		numCode = strings.TrimSpace(item.NumCode)
+	}
+
+	numCodeValid := len(numCode) == numCodeLength && isDigits(numCode)
+	if numCode != "" && !numCodeValid {
+		report(FieldNumCode, "%q is not a three-digit code", numCode)
+	}
+
+	if charCode != "" {
+		isoNumCode, known := v.iso[charCode]
+
+		switch {
+		case len(charCode) != charCodeLength || strings.ToUpper(charCode) != charCode:
+			report(FieldCharCode, "%q is not a three-letter upper-case code", charCode)
+		case v.checkISO && !known:
+			report(FieldCharCode, "%q is not an ISO 4217 currency", charCode)
+		case v.checkISO && numCodeValid && numCode != isoNumCode:
+			report(FieldNumCode, "%q does not match ISO 4217 code %s for %s", numCode, isoNumCode, charCode)
+		}
+
+		if first, seen := v.charCodes[charCode]; seen {
+			report(FieldCharCode, "%q duplicates valute #%d", charCode, first)
+		} else {
+			v.charCodes[charCode] = index
+		}
+	}
+
+	if numCode != "" {
+		if first, seen := v.numCodes[numCode]; seen {
+			report(FieldNumCode, "%q duplicates valute #%d", numCode, first)
+		} else {
+			v.numCodes[numCode] = index
+		}
+	}
+}
+
+func (v *Validator) checkNumbers(item currencyhandler.CurrencyItem, report func(string, string, ...any)) {
+	if item.Nominal != "" {
+		if _, err := item.ParseNominal(); err != nil {
+			report(FieldNominal, "%q is not a positive integer", item.Nominal)
+		}
+	}
+
+	for _, field := range []string{FieldValue, FieldVunitRate} {
+		text := fieldValue(item, field)
+		if text == "" {
+			continue
+		}
+
+		value, err := decimal.Parse(text)
+
+		switch {
+		case err != nil:
+			report(field, "%q is not a decimal number", text)
+		case value.Sign() <= 0:
+			report(field, "%q must be positive", text)
+		}
+	}
+}
+
+// Finish returns document-level violations.
+func (v *Validator) Finish() []Violation {
+	if v.count >= v.minRecords {
+		return nil
+	}
+
+	message := fmt.Sprintf("found %d Valute record(s), at least %d required", v.count, v.minRecords)
+	if v.documentTag != "" {
+		message = v.documentTag + ": " + message
+	}
+
+	return []Violation{{Index: apperrors.NoIndex, CharCode: "", Field: "", Message: message}}
+}
+
+// Validate checks a whole list and reports every violation at once.
+func Validate(list currencyhandler.CurrencyList, rules Rules) error {
+	validator, err := New(rules)
+	if err != nil {
+		return err
+	}
+
+	validator.Reset(list.Date)
+
+	var violations []Violation
+
+	for index, item := range list.Items {
+		violations = append(violations, validator.Check(index, item)...)
+	}
+
+	violations = append(violations, validator.Finish()...)
+
+	return AsError(violations)
+}
+
+func AsError(violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &Error{Violations: violations}
+}
+
+func fieldValue(item currencyhandler.CurrencyItem, field string) string {
+	switch field {
+	case FieldNumCode:
+		return item.NumCode
+	case FieldCharCode:
+		return item.CharCode
+	case FieldNominal:
+		return item.Nominal
+	case FieldName:
+		return item.Name
+	case FieldValue:
+		return item.Value
+	case FieldVunitRate:
+		return item.VunitRate
+	default:
+		return ""
+	}
+}
+
+func knownField(field string) bool {
+	switch field {
+	case FieldNumCode, FieldCharCode, FieldNominal, FieldName, FieldValue, FieldVunitRate:
+		return true
+	default:
+		return false
+	}
+}
+
+func isDigits(text string) bool {
+	_, err := strconv.ParseUint(text, 10, 16)
+
+	return err == nil
+}
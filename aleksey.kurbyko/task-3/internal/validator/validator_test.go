@@ -0,0 +1,175 @@
+package validator_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/aleksey.kurbyko/task-3/internal/validator"
+	"github.com/stretchr/testify/require"
+)
+
+func item(numCode, charCode, value string) currencyhandler.CurrencyItem {
+	return currencyhandler.CurrencyItem{
+		ID:        "",
+		NumCode:   numCode,
+		CharCode:  charCode,
+		Nominal:   "1",
+		Name:      charCode + " name",
+		Value:     value,
+		VunitRate: "",
+	}
+}
+
+func list(items ...currencyhandler.CurrencyItem) currencyhandler.CurrencyList {
+	return currencyhandler.CurrencyList{
+		XMLName: xml.Name{Space: "", Local: "ValCurs"},
+		Date:    "18.10.2026",
+		Name:    "",
+		Base:    currencyhandler.BaseRUB,
+		Items:   items,
+	}
+}
+
+func noRules() validator.Rules {
+	return validator.Rules{MinRecords: nil, Required: nil, AllowNonISO: false}
+}
+
+func violations(t *testing.T, err error) []validator.Violation {
+	t.Helper()
+
+	var failure *validator.Error
+
+	require.ErrorAs(t, err, &failure)
+	require.ErrorIs(t, err, apperrors.ErrValidate)
+	require.ErrorIs(t, err, validator.ErrViolations)
+
+	return failure.Violations
+}
+
+func withNumCode() validator.Rules {
+	rules := noRules()
+	rules.Required = []string{validator.FieldNumCode, validator.FieldCharCode, validator.FieldValue}
+
+	return rules
+}
+
+func TestValidateISO4217(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name  string
+		item  currencyhandler.CurrencyItem
+		rules validator.Rules
+		field string
+	}{
+		{name: "unknown code", item: item("999", "ZZZ", "1"), rules: noRules(), field: validator.FieldCharCode},
+		{name: "lower case", item: item("840", "usd", "1"), rules: noRules(), field: validator.FieldCharCode},
+		{name: "numeric mismatch", item: item("978", "USD", "1"), rules: withNumCode(), field: validator.FieldNumCode},
+		{name: "numeric not three digits", item: item("84", "USD", "1"), rules: withNumCode(), field: validator.FieldNumCode},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			found := violations(t, validator.Validate(list(test.item), test.rules))
+			require.Len(t, found, 1)
+			require.Equal(t, test.field, found[0].Field)
+			require.Equal(t, 0, found[0].Index)
+		})
+	}
+
+	rules := noRules()
+	rules.AllowNonISO = true
+
+	require.NoError(t, validator.Validate(list(item("999", "ZZZ", "1")), rules))
+	require.NoError(t, validator.Validate(list(item("840", "USD", "90,5567")), noRules()))
+	require.NoError(t, validator.Validate(list(item("84", "USD", "1")), noRules()), "NumCode is only checked on request")
+}
+
+func TestValidateMinimalFeed(t *testing.T) {
+	t.Parallel()
+
+	minimal := currencyhandler.CurrencyItem{
+		ID:        "",
+		NumCode:   "",
+		CharCode:  "USD",
+		Nominal:   "",
+		Name:      "",
+		Value:     "90,5567",
+		VunitRate: "",
+	}
+
+	require.NoError(t, validator.Validate(list(minimal), noRules()))
+
+	found := violations(t, validator.Validate(list(minimal), withNumCode()))
+	require.Len(t, found, 1)
+	require.Equal(t, validator.FieldNumCode, found[0].Field)
+}
+
+func TestValidateRequired(t *testing.T) {
+	t.Parallel()
+
+	missing := item("840", "USD", "")
+	missing.Name = " "
+
+	found := violations(t, validator.Validate(list(missing), noRules()))
+	require.Len(t, found, 1)
+	require.Equal(t, validator.FieldValue, found[0].Field)
+
+	rules := noRules()
+	rules.Required = []string{validator.FieldName}
+
+	found = violations(t, validator.Validate(list(missing), rules))
+	require.Len(t, found, 1)
+	require.Equal(t, validator.FieldName, found[0].Field)
+
+	rules.Required = []string{validator.FieldCharCode}
+
+	missing.Value = "1"
+	require.NoError(t, validator.Validate(list(missing), rules))
+
+	rules.Required = []string{"Rate"}
+
+	err := validator.Validate(list(missing), rules)
+	require.ErrorIs(t, err, apperrors.ErrConfig)
+	require.ErrorIs(t, err, validator.ErrUnknownField)
+}
+
+func TestValidateDuplicates(t *testing.T) {
+	t.Parallel()
+
+	found := violations(t, validator.Validate(list(
+		item("840", "USD", "1"),
+		item("978", "EUR", "1"),
+		item("840", "USD", "2"),
+	), withNumCode()))
+	require.Len(t, found, 2)
+	require.Equal(t, 2, found[0].Index)
+	require.Equal(t, validator.FieldCharCode, found[0].Field)
+	require.Contains(t, found[0].Message, "duplicates valute #0")
+	require.Equal(t, validator.FieldNumCode, found[1].Field)
+}
+
+func TestValidateNumbersAndCount(t *testing.T) {
+	t.Parallel()
+
+	negative := item("840", "USD", "-1")
+	negative.Nominal = "0"
+
+	found := violations(t, validator.Validate(list(negative), noRules()))
+	require.Len(t, found, 2)
+	require.Equal(t, validator.FieldNominal, found[0].Field)
+	require.Equal(t, validator.FieldValue, found[1].Field)
+
+	found = violations(t, validator.Validate(list(), noRules()))
+	require.Len(t, found, 1)
+	require.Equal(t, apperrors.NoIndex, found[0].Index)
+	require.Contains(t, found[0].String(), "18.10.2026: found 0 Valute record(s)")
+
+	none := 0
+	rules := noRules()
+	rules.MinRecords = &none
+
+	require.NoError(t, validator.Validate(list(), rules))
+}
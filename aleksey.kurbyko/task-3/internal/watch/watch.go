@@ -0,0 +1,103 @@
+package watch
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+)
+
+type fileState struct {
+	missing bool
+	size    int64
+	modTime time.Time
+	sum     [sha256.Size]byte
+}
+
+// Snapshot remembers the size, modification time and content hash of a
+// set of files between polls.
+type Snapshot struct {
+	files map[string]fileState
+}
+
+func NewSnapshot() *Snapshot {
+	return &Snapshot{files: make(map[string]fileState)}
+}
+
+// Update records the current state of paths and reports whether it
+// differs from the previous call. Contents are hashed only when the size
+// or mtime moved, so touching a file without editing it is not a change.
+func (s *Snapshot) Update(paths []string) bool {
+	next := make(map[string]fileState, len(paths))
+	changed := len(paths) != len(s.files)
+
+	for _, path := range paths {
+		previous, known := s.files[path]
+		state := stat(path, previous, known)
+		next[path] = state
+
+		if !known || !state.sameContent(previous) {
+			changed = true
+		}
+	}
+
+	s.files = next
+
+	return changed
+}
+
+func stat(path string, previous fileState, known bool) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{missing: errors.Is(err, fs.ErrNotExist), size: -1, modTime: time.Time{}, sum: [sha256.Size]byte{}}
+	}
+
+	state := fileState{missing: false, size: info.Size(), modTime: info.ModTime(), sum: previous.sum}
+	if known && !previous.missing && state.size == previous.size && state.modTime.Equal(previous.modTime) {
+		return state
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		state.sum = [sha256.Size]byte{}
+
+		return state
+	}
+
+	state.sum = sha256.Sum256(data)
+
+	return state
+}
+
+func (f fileState) sameContent(other fileState) bool {
+	return f.missing == other.missing && f.size == other.size && f.sum == other.sum
+}
+
+// Debounce holds back a burst of changes until none has been seen for
+// delay, so that an editor writing a file in several steps causes one
+// run.
+type Debounce struct {
+	delay     time.Duration
+	changedAt time.Time
+}
+
+func NewDebounce(delay time.Duration) *Debounce {
+	return &Debounce{delay: delay, changedAt: time.Time{}}
+}
+
+// Tick records the outcome of a poll made at now and reports whether a
+// run is due.
+func (d *Debounce) Tick(now time.Time, changed bool) bool {
+	if changed {
+		d.changedAt = now
+	}
+
+	if d.changedAt.IsZero() || now.Sub(d.changedAt) < d.delay {
+		return false
+	}
+
+	d.changedAt = time.Time{}
+
+	return true
+}
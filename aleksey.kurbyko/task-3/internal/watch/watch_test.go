@@ -0,0 +1,68 @@
+package watch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aleksey.kurbyko/task-3/internal/watch"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotUpdate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "daily.xml")
+	config := filepath.Join(dir, "config.yaml")
+
+	require.NoError(t, os.WriteFile(input, []byte("<ValCurs/>"), 0o600))
+	require.NoError(t, os.WriteFile(config, []byte("a: 1"), 0o600))
+
+	snapshot := watch.NewSnapshot()
+	paths := []string{input, config}
+
+	require.True(t, snapshot.Update(paths), "first poll sees new files")
+	require.False(t, snapshot.Update(paths), "nothing moved")
+
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(input, later, later))
+	require.False(t, snapshot.Update(paths), "touch without an edit")
+
+	edited := later.Add(time.Minute)
+	require.NoError(t, os.WriteFile(input, []byte("<ValCurz/>"), 0o600))
+	require.NoError(t, os.Chtimes(input, edited, edited))
+	require.True(t, snapshot.Update(paths), "edit of the same size")
+	require.False(t, snapshot.Update(paths))
+
+	require.NoError(t, os.Remove(config))
+	require.True(t, snapshot.Update(paths), "removed file")
+	require.False(t, snapshot.Update(paths), "still removed")
+
+	require.NoError(t, os.WriteFile(config, []byte("a: 1"), 0o600))
+	require.True(t, snapshot.Update(paths), "recreated file")
+
+	require.True(t, snapshot.Update(paths[:1]), "fewer watched paths")
+	require.False(t, snapshot.Update(paths[:1]))
+}
+
+func TestDebounce(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) time.Time { return start.Add(offset) }
+
+	quiet := watch.NewDebounce(500 * time.Millisecond)
+
+	require.False(t, quiet.Tick(at(0), false), "no change, no run")
+	require.False(t, quiet.Tick(at(100*time.Millisecond), true))
+	require.False(t, quiet.Tick(at(300*time.Millisecond), true), "a burst keeps pushing the run back")
+	require.False(t, quiet.Tick(at(700*time.Millisecond), false))
+	require.True(t, quiet.Tick(at(800*time.Millisecond), false), "quiet for the delay")
+	require.False(t, quiet.Tick(at(2*time.Second), false), "runs once per burst")
+
+	immediate := watch.NewDebounce(0)
+	require.True(t, immediate.Tick(at(0), true))
+	require.False(t, immediate.Tick(at(time.Millisecond), false))
+}
@@ -0,0 +1,56 @@
+package writer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/aleksey.kurbyko/task-3/internal/timeseries"
+)
+
+type CSVWriter struct{}
+
+func (CSVWriter) Write(out io.Writer, table Table) error {
+	records := make([][]string, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		records = append(records, row.Strings())
+	}
+
+	return writeCSV(out, table.Columns, records)
+}
+
+func (CSVWriter) WriteSeries(out io.Writer, series []timeseries.Summary) error {
+	header := []string{"char_code", "date", "unit_rate", "change", "change_pct", "min", "max", "mean"}
+	records := make([][]string, 0, len(series))
+
+	for _, summary := range series {
+		for _, point := range summary.Points {
+			records = append(records, []string{
+				summary.CharCode,
+				point.Date,
+				point.UnitRate.String(),
+				point.Change.String(),
+				point.ChangePct.String(),
+				summary.Min.String(),
+				summary.Max.String(),
+				summary.Mean.String(),
+			})
+		}
+	}
+
+	return writeCSV(out, header, records)
+}
+
+func writeCSV(out io.Writer, header []string, records [][]string) error {
+	csvWriter := csv.NewWriter(out)
+
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("write csv header: %w", err)
+	}
+
+	if err := csvWriter.WriteAll(records); err != nil {
+		return fmt.Errorf("write csv records: %w", err)
+	}
+
+	return nil
+}
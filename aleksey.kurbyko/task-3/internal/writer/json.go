@@ -0,0 +1,54 @@
+package writer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/aleksey.kurbyko/task-3/internal/timeseries"
+)
+
+const jsonIndent = "  "
+
+type JSONWriter struct{}
+
+func (JSONWriter) Write(out io.Writer, table Table) error {
+	return writeIndentedJSON(out, table.Rows)
+}
+
+func (JSONWriter) WriteSeries(out io.Writer, series []timeseries.Summary) error {
+	return writeIndentedJSON(out, series)
+}
+
+func writeIndentedJSON(out io.Writer, value any) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", jsonIndent)
+
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+
+	return nil
+}
+
+type JSONLinesWriter struct{}
+
+func (JSONLinesWriter) Write(out io.Writer, table Table) error {
+	return writeJSONLines(out, table.Rows)
+}
+
+func (JSONLinesWriter) WriteSeries(out io.Writer, series []timeseries.Summary) error {
+	return writeJSONLines(out, series)
+}
+
+func writeJSONLines[T any](out io.Writer, records []T) error {
+	encoder := json.NewEncoder(out)
+
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("encode json line: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package writer
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnknownField = errors.New("unknown output field")
+	ErrBadFieldName = errors.New("invalid output key")
+	ErrDuplicateKey = errors.New("duplicate output key")
+)
+
+// Projection picks the output fields, in order, and optionally renames
+// their keys. An empty Fields list means all fields. Every output key
+// must be unique.
+type Projection struct {
+	Fields []string          `yaml:"fields"`
+	Rename map[string]string `yaml:"rename"`
+}
+
+// Row is one output record with ordered keys. Empty string values are
+// left out of JSON, YAML and XML, like omitempty.
+type Row struct {
+	keys   []string
+	values []any
+}
+
+type Table struct {
+	Columns []string
+	Rows    []Row
+}
+
+func fieldNames() []string {
+	return []string{"id", "num_code", "char_code", "name", "nominal", "value", "unit_rate"}
+}
+
+func fieldValue(currency dataprocessor.JSONCurrency, field string) any {
+	switch field {
+	case "id":
+		return currency.ID
+	case "num_code":
+		return currency.NumCode
+	case "char_code":
+		return currency.CharCode
+	case "name":
+		return currency.Name
+	case "nominal":
+		return currency.Nominal
+	case "value":
+		return currency.Value
+	case "unit_rate":
+		return currency.UnitRate
+	default:
+		return nil
+	}
+}
+
+func (p Projection) Validate() error {
+	known := make(map[string]bool)
+	for _, field := range fieldNames() {
+		known[field] = true
+	}
+
+	keyPattern := regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+	for _, field := range p.Fields {
+		if !known[field] {
+			return apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("%w: %q", ErrUnknownField, field))
+		}
+	}
+
+	for field, key := range p.Rename {
+		if !known[field] {
+			return apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("%w: %q", ErrUnknownField, field))
+		}
+
+		if !keyPattern.MatchString(key) {
+			return apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("%w: %q", ErrBadFieldName, key))
+		}
+	}
+
+	seen := make(map[string]bool)
+
+	for _, key := range p.columns(p.fields()) {
+		if seen[key] {
+			return apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("%w: %q", ErrDuplicateKey, key))
+		}
+
+		seen[key] = true
+	}
+
+	return nil
+}
+
+func (p Projection) fields() []string {
+	if len(p.Fields) == 0 {
+		return fieldNames()
+	}
+
+	return p.Fields
+}
+
+func (p Projection) columns(fields []string) []string {
+	columns := make([]string, 0, len(fields))
+
+	for _, field := range fields {
+		key := field
+		if renamed, ok := p.Rename[field]; ok {
+			key = renamed
+		}
+
+		columns = append(columns, key)
+	}
+
+	return columns
+}
+
+func (p Projection) Table(currencies []dataprocessor.JSONCurrency) (Table, error) {
+	if err := p.Validate(); err != nil {
+		return Table{Columns: nil, Rows: nil}, err
+	}
+
+	fields := p.fields()
+	columns := p.columns(fields)
+
+	rows := make([]Row, 0, len(currencies))
+
+	for _, currency := range currencies {
+		values := make([]any, 0, len(fields))
+		for _, field := range fields {
+			values = append(values, fieldValue(currency, field))
+		}
+
+		rows = append(rows, Row{keys: columns, values: values})
+	}
+
+	return Table{Columns: columns, Rows: rows}, nil
+}
+
+func (r Row) present(index int) bool {
+	text, isString := r.values[index].(string)
+
+	return !isString || text != ""
+}
+
+func (r Row) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.WriteByte('{')
+
+	written := 0
+
+	for index, key := range r.keys {
+		if !r.present(index) {
+			continue
+		}
+
+		keyData, err := json.Marshal(key)
+		if err != nil {
+			return nil, fmt.Errorf("marshal key %q: %w", key, err)
+		}
+
+		valueData, err := json.Marshal(r.values[index])
+		if err != nil {
+			return nil, fmt.Errorf("marshal %q: %w", key, err)
+		}
+
+		if written > 0 {
+			buffer.WriteByte(',')
+		}
+
+		buffer.Write(keyData)
+		buffer.WriteByte(':')
+		buffer.Write(valueData)
+
+		written++
+	}
+
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+func (r Row) MarshalYAML() (any, error) {
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+
+	for index, key := range r.keys {
+		if !r.present(index) {
+			continue
+		}
+
+		value := &yaml.Node{}
+		if err := value.Encode(r.values[index]); err != nil {
+			return nil, fmt.Errorf("marshal %q: %w", key, err)
+		}
+
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	}
+
+	return mapping, nil
+}
+
+func (r Row) MarshalXML(encoder *xml.Encoder, start xml.StartElement) error {
+	if err := encoder.EncodeToken(start); err != nil {
+		return fmt.Errorf("encode xml row: %w", err)
+	}
+
+	for index, key := range r.keys {
+		if !r.present(index) {
+			continue
+		}
+
+		element := xml.StartElement{Name: xml.Name{Space: "", Local: key}, Attr: nil}
+		if err := encoder.EncodeElement(fmt.Sprint(r.values[index]), element); err != nil {
+			return fmt.Errorf("encode xml field %q: %w", key, err)
+		}
+	}
+
+	if err := encoder.EncodeToken(start.End()); err != nil {
+		return fmt.Errorf("encode xml row: %w", err)
+	}
+
+	return nil
+}
+
+func (r Row) Strings() []string {
+	record := make([]string, 0, len(r.values))
+	for _, value := range r.values {
+		record = append(record, fmt.Sprint(value))
+	}
+
+	return record
+}
@@ -0,0 +1,126 @@
+package writer_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+	"github.com/aleksey.kurbyko/task-3/internal/writer"
+	"github.com/stretchr/testify/require"
+)
+
+func currencies(t *testing.T) []dataprocessor.JSONCurrency {
+	t.Helper()
+
+	value, err := decimal.Parse("60,1020")
+	require.NoError(t, err)
+
+	unitRate, err := decimal.Parse("0,60102")
+	require.NoError(t, err)
+
+	return []dataprocessor.JSONCurrency{{
+		ID:       "R01820",
+		NumCode:  392,
+		CharCode: "JPY",
+		Name:     "",
+		Nominal:  100,
+		Value:    value,
+		UnitRate: unitRate,
+	}}
+}
+
+func TestProjectionTable(t *testing.T) {
+	t.Parallel()
+
+	projection := writer.Projection{
+		Fields: []string{"char_code", "unit_rate", "name"},
+		Rename: map[string]string{"unit_rate": "rate"},
+	}
+
+	table, err := projection.Table(currencies(t))
+	require.NoError(t, err)
+	require.Equal(t, []string{"char_code", "rate", "name"}, table.Columns)
+	require.Len(t, table.Rows, 1)
+	require.Equal(t, []string{"JPY", "0.60102", ""}, table.Rows[0].Strings())
+
+	data, err := json.Marshal(table.Rows[0])
+	require.NoError(t, err)
+	require.Equal(t, `{"char_code":"JPY","rate":0.60102}`, string(data))
+
+	all, err := writer.Projection{Fields: nil, Rename: nil}.Table(currencies(t))
+	require.NoError(t, err)
+	require.Equal(t, []string{"id", "num_code", "char_code", "name", "nominal", "value", "unit_rate"}, all.Columns)
+}
+
+func TestProjectionValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name       string
+		projection writer.Projection
+		want       error
+	}{
+		{
+			name:       "unknown field",
+			projection: writer.Projection{Fields: []string{"rate"}, Rename: nil},
+			want:       writer.ErrUnknownField,
+		},
+		{
+			name:       "unknown rename",
+			projection: writer.Projection{Fields: nil, Rename: map[string]string{"rate": "x"}},
+			want:       writer.ErrUnknownField,
+		},
+		{
+			name:       "bad key",
+			projection: writer.Projection{Fields: nil, Rename: map[string]string{"value": "1st value"}},
+			want:       writer.ErrBadFieldName,
+		},
+		{
+			name:       "same target",
+			projection: writer.Projection{Fields: nil, Rename: map[string]string{"value": "rate", "unit_rate": "rate"}},
+			want:       writer.ErrDuplicateKey,
+		},
+		{
+			name: "target is another field",
+			projection: writer.Projection{
+				Fields: []string{"char_code", "value"},
+				Rename: map[string]string{"value": "char_code"},
+			},
+			want: writer.ErrDuplicateKey,
+		},
+		{
+			name:       "repeated field",
+			projection: writer.Projection{Fields: []string{"value", "value"}, Rename: nil},
+			want:       writer.ErrDuplicateKey,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := test.projection.Validate()
+			require.ErrorIs(t, err, test.want)
+			require.ErrorIs(t, err, apperrors.ErrConfig)
+		})
+	}
+
+	swapped := writer.Projection{Fields: nil, Rename: map[string]string{"value": "unit_rate", "unit_rate": "value"}}
+	require.NoError(t, swapped.Validate())
+}
+
+func TestWriteProjectedCSV(t *testing.T) {
+	t.Parallel()
+
+	table, err := writer.Projection{Fields: []string{"char_code", "value"}, Rename: nil}.Table(currencies(t))
+	require.NoError(t, err)
+
+	outputWriter, err := writer.New(writer.FormatCSV)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+
+	require.NoError(t, outputWriter.Write(&out, table))
+	require.Equal(t, "char_code,value\nJPY,60.102\n", out.String())
+}
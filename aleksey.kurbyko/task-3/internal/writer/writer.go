@@ -0,0 +1,85 @@
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/timeseries"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown output format")
+	ErrNoSeries      = errors.New("output format cannot write a time series")
+)
+
+const (
+	FormatJSON  = "json"
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+	FormatYAML  = "yaml"
+	FormatXML   = "xml"
+)
+
+type Writer interface {
+	Write(out io.Writer, table Table) error
+}
+
+// SeriesWriter is implemented by the writers that can also encode a
+// multi-day summary.
+type SeriesWriter interface {
+	WriteSeries(out io.Writer, series []timeseries.Summary) error
+}
+
+// ForSeries returns the series encoder of outputWriter.
+func ForSeries(outputWriter Writer) (SeriesWriter, error) {
+	seriesWriter, ok := outputWriter.(SeriesWriter)
+	if !ok {
+		return nil, apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("%w: %T", ErrNoSeries, outputWriter))
+	}
+
+	return seriesWriter, nil
+}
+
+func New(format string) (Writer, error) {
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return JSONWriter{}, nil
+	case FormatJSONL, "ndjson":
+		return JSONLinesWriter{}, nil
+	case FormatCSV:
+		return CSVWriter{}, nil
+	case FormatYAML, "yml":
+		return YAMLWriter{}, nil
+	case FormatXML:
+		return XMLWriter{}, nil
+	default:
+		return nil, apperrors.Wrap(apperrors.ErrConfig, fmt.Errorf("%w: %q", ErrUnknownFormat, format))
+	}
+}
+
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	case ".csv":
+		return FormatCSV
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".xml":
+		return FormatXML
+	default:
+		return FormatJSON
+	}
+}
+
+func ForOutput(format string, path string) (Writer, error) {
+	if format == "" {
+		format = FormatFromPath(path)
+	}
+
+	return New(format)
+}
@@ -0,0 +1,98 @@
+package writer_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/writer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatFromPath(t *testing.T) {
+	t.Parallel()
+
+	for path, want := range map[string]string{
+		"out.json":        writer.FormatJSON,
+		"out":             writer.FormatJSON,
+		"out.JSONL":       writer.FormatJSONL,
+		"out.ndjson":      writer.FormatJSONL,
+		"dir/out.csv":     writer.FormatCSV,
+		"out.yml":         writer.FormatYAML,
+		"out.yaml":        writer.FormatYAML,
+		"out.xml":         writer.FormatXML,
+		"out.rejects.txt": writer.FormatJSON,
+	} {
+		require.Equal(t, want, writer.FormatFromPath(path), path)
+	}
+}
+
+func TestForOutput(t *testing.T) {
+	t.Parallel()
+
+	chosen, err := writer.ForOutput("", "rates.csv")
+	require.NoError(t, err)
+	require.IsType(t, writer.CSVWriter{}, chosen)
+
+	chosen, err = writer.ForOutput("YAML", "rates.csv")
+	require.NoError(t, err, "an explicit format wins over the extension")
+	require.IsType(t, writer.YAMLWriter{}, chosen)
+
+	_, err = writer.ForOutput("toml", "rates.toml")
+	require.ErrorIs(t, err, writer.ErrUnknownFormat)
+	require.ErrorIs(t, err, apperrors.ErrConfig)
+}
+
+func TestWriteFormats(t *testing.T) {
+	t.Parallel()
+
+	table, err := writer.Projection{Fields: []string{"id", "char_code", "nominal", "unit_rate"}, Rename: nil}.
+		Table(currencies(t))
+	require.NoError(t, err)
+
+	for format, want := range map[string]string{
+		writer.FormatJSON: "[\n  {\n    \"id\": \"R01820\",\n    \"char_code\": \"JPY\",\n" +
+			"    \"nominal\": 100,\n    \"unit_rate\": 0.60102\n  }\n]\n",
+		writer.FormatJSONL: `{"id":"R01820","char_code":"JPY","nominal":100,"unit_rate":0.60102}` + "\n",
+		writer.FormatCSV:   "id,char_code,nominal,unit_rate\nR01820,JPY,100,0.60102\n",
+		writer.FormatYAML:  "- id: R01820\n  char_code: JPY\n  nominal: 100\n  unit_rate: 0.60102\n",
+		writer.FormatXML: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<currencies>\n  <currency>\n" +
+			"    <id>R01820</id>\n    <char_code>JPY</char_code>\n    <nominal>100</nominal>\n" +
+			"    <unit_rate>0.60102</unit_rate>\n  </currency>\n</currencies>\n",
+	} {
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+
+			outputWriter, err := writer.New(format)
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+
+			require.NoError(t, outputWriter.Write(&out, table))
+			require.Equal(t, want, out.String())
+		})
+	}
+}
+
+type tableOnly struct{}
+
+func (tableOnly) Write(io.Writer, writer.Table) error {
+	return nil
+}
+
+func TestForSeries(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []string{writer.FormatJSON, writer.FormatJSONL, writer.FormatCSV, writer.FormatYAML, writer.FormatXML} {
+		outputWriter, err := writer.New(format)
+		require.NoError(t, err)
+
+		_, err = writer.ForSeries(outputWriter)
+		require.NoError(t, err, format)
+	}
+
+	_, err := writer.ForSeries(tableOnly{})
+	require.ErrorIs(t, err, writer.ErrNoSeries)
+	require.ErrorIs(t, err, apperrors.ErrConfig)
+}
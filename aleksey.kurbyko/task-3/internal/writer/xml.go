@@ -0,0 +1,56 @@
+package writer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/aleksey.kurbyko/task-3/internal/timeseries"
+)
+
+const xmlIndent = "  "
+
+type xmlDocument struct {
+	XMLName    xml.Name `xml:"currencies"`
+	Currencies []Row    `xml:"currency"`
+}
+
+type xmlSeriesDocument struct {
+	XMLName xml.Name             `xml:"series"`
+	Series  []timeseries.Summary `xml:"currency"`
+}
+
+type XMLWriter struct{}
+
+func (XMLWriter) Write(out io.Writer, table Table) error {
+	return writeXML(out, xmlDocument{
+		XMLName:    xml.Name{Space: "", Local: ""},
+		Currencies: table.Rows,
+	})
+}
+
+func (XMLWriter) WriteSeries(out io.Writer, series []timeseries.Summary) error {
+	return writeXML(out, xmlSeriesDocument{
+		XMLName: xml.Name{Space: "", Local: ""},
+		Series:  series,
+	})
+}
+
+func writeXML(out io.Writer, document any) error {
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return fmt.Errorf("write xml header: %w", err)
+	}
+
+	encoder := xml.NewEncoder(out)
+	encoder.Indent("", xmlIndent)
+
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("encode xml: %w", err)
+	}
+
+	if _, err := io.WriteString(out, "\n"); err != nil {
+		return fmt.Errorf("write xml trailer: %w", err)
+	}
+
+	return nil
+}
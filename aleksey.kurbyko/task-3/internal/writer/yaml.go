@@ -0,0 +1,36 @@
+package writer
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/aleksey.kurbyko/task-3/internal/timeseries"
+	"gopkg.in/yaml.v3"
+)
+
+const yamlIndent = 2
+
+type YAMLWriter struct{}
+
+func (YAMLWriter) Write(out io.Writer, table Table) error {
+	return writeYAML(out, table.Rows)
+}
+
+func (YAMLWriter) WriteSeries(out io.Writer, series []timeseries.Summary) error {
+	return writeYAML(out, series)
+}
+
+func writeYAML(out io.Writer, value any) error {
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(yamlIndent)
+
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("encode yaml: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("close yaml encoder: %w", err)
+	}
+
+	return nil
+}
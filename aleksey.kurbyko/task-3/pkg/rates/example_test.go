@@ -0,0 +1,151 @@
+package rates_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aleksey.kurbyko/task-3/pkg/rates"
+)
+
+const feed = `<?xml version="1.0" encoding="UTF-8"?>
+<ValCurs Date="18.10.2026" name="Foreign Currency Market">
+  <Valute ID="R01235">
+    <NumCode>840</NumCode>
+    <CharCode>USD</CharCode>
+    <Nominal>1</Nominal>
+    <Name>US Dollar</Name>
+    <Value>90,5567</Value>
+  </Valute>
+  <Valute ID="R01239">
+    <NumCode>978</NumCode>
+    <CharCode>EUR</CharCode>
+    <Nominal>1</Nominal>
+    <Name>Euro</Name>
+    <Value>98,1234</Value>
+  </Valute>
+  <Valute ID="R01820">
+    <NumCode>392</NumCode>
+    <CharCode>JPY</CharCode>
+    <Nominal>100</Nominal>
+    <Name>Japanese Yen</Name>
+    <Value>60,1020</Value>
+  </Valute>
+</ValCurs>`
+
+func ExampleDecode() {
+	decoded, err := rates.Decode(context.Background(), strings.NewReader(feed))
+	if err != nil {
+		fmt.Println("error:", err)
+
+		return
+	}
+
+	fmt.Println(decoded.Date.Format("2006-01-02"), decoded.Base)
+
+	for _, currency := range decoded.Currencies {
+		fmt.Println(currency.CharCode, currency.UnitRate)
+	}
+
+	// Output:
+	// 2026-10-18 RUB
+	// USD 90.5567
+	// EUR 98.1234
+	// JPY 0.60102
+}
+
+func ExampleRates_Sort() {
+	decoded, err := rates.Decode(context.Background(), strings.NewReader(feed))
+	if err != nil {
+		fmt.Println("error:", err)
+
+		return
+	}
+
+	sorted, err := decoded.Sort(rates.SortKey{Key: "char_code", Direction: "asc"})
+	if err != nil {
+		fmt.Println("error:", err)
+
+		return
+	}
+
+	for _, currency := range sorted.Currencies {
+		fmt.Println(currency.CharCode)
+	}
+
+	// Output:
+	// EUR
+	// JPY
+	// USD
+}
+
+func ExampleRates_Filter() {
+	decoded, err := rates.Decode(context.Background(), strings.NewReader(feed))
+	if err != nil {
+		fmt.Println("error:", err)
+
+		return
+	}
+
+	filtered, err := decoded.Filter(rates.Filter{
+		Include:      nil,
+		Exclude:      []string{"EUR"},
+		IncludeRegex: "^[EU]",
+		ExcludeRegex: "",
+	})
+	if err != nil {
+		fmt.Println("error:", err)
+
+		return
+	}
+
+	for _, currency := range filtered.Currencies {
+		fmt.Println(currency.CharCode)
+	}
+
+	// Output:
+	// USD
+}
+
+func ExampleRates_Encode() {
+	decoded, err := rates.Decode(context.Background(), strings.NewReader(feed))
+	if err != nil {
+		fmt.Println("error:", err)
+
+		return
+	}
+
+	if err := decoded.Encode(os.Stdout, rates.FormatCSV); err != nil {
+		fmt.Println("error:", err)
+	}
+
+	// Output:
+	// id,num_code,char_code,name,nominal,value,unit_rate
+	// R01235,840,USD,US Dollar,1,90.5567,90.5567
+	// R01239,978,EUR,Euro,1,98.1234,98.1234
+	// R01820,392,JPY,Japanese Yen,100,60.102,0.60102
+}
+
+func ExampleRates_EncodeFields() {
+	decoded, err := rates.Decode(context.Background(), strings.NewReader(feed))
+	if err != nil {
+		fmt.Println("error:", err)
+
+		return
+	}
+
+	projection := rates.Projection{
+		Fields: []string{"num_code", "char_code", "unit_rate"},
+		Rename: map[string]string{"num_code": "numCode", "char_code": "charCode", "unit_rate": "unitRate"},
+	}
+
+	if err := decoded.EncodeFields(os.Stdout, rates.FormatJSONL, projection); err != nil {
+		fmt.Println("error:", err)
+	}
+
+	// Output:
+	// {"numCode":840,"charCode":"USD","unitRate":90.5567}
+	// {"numCode":978,"charCode":"EUR","unitRate":98.1234}
+	// {"numCode":392,"charCode":"JPY","unitRate":0.60102}
+}
@@ -0,0 +1,199 @@
+// Package rates is the library form of the converter: it decodes a CBR
+// or ECB rates feed, filters and sorts the currencies and encodes them in
+// any of the service output formats. Functions return errors instead of
+// exiting, and decoding honours context cancellation.
+package rates
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aleksey.kurbyko/task-3/internal/apperrors"
+	"github.com/aleksey.kurbyko/task-3/internal/currencyhandler"
+	"github.com/aleksey.kurbyko/task-3/internal/dataprocessor"
+	"github.com/aleksey.kurbyko/task-3/internal/decimal"
+	"github.com/aleksey.kurbyko/task-3/internal/validator"
+	"github.com/aleksey.kurbyko/task-3/internal/writer"
+)
+
+type (
+	Currency   = dataprocessor.JSONCurrency
+	Decimal    = decimal.Decimal
+	SortKey    = dataprocessor.SortKey
+	Filter     = dataprocessor.Filter
+	Projection = writer.Projection
+	Validation = validator.Rules
+)
+
+// Stage errors, usable with errors.Is on anything this package returns.
+var (
+	ErrConfig    = apperrors.ErrConfig
+	ErrInput     = apperrors.ErrInput
+	ErrDecode    = apperrors.ErrDecode
+	ErrValidate  = apperrors.ErrValidate
+	ErrTransform = apperrors.ErrTransform
+	ErrWrite     = apperrors.ErrWrite
+)
+
+const (
+	FormatAuto    = currencyhandler.FormatAuto
+	FormatCBRXML  = currencyhandler.FormatCBRXML
+	FormatECBXML  = currencyhandler.FormatECBXML
+	FormatCBRJSON = currencyhandler.FormatCBRJSON
+
+	FormatJSON  = writer.FormatJSON
+	FormatJSONL = writer.FormatJSONL
+	FormatCSV   = writer.FormatCSV
+	FormatYAML  = writer.FormatYAML
+	FormatXML   = writer.FormatXML
+
+	OnInvalidFail    = dataprocessor.OnInvalidFail
+	OnInvalidSkip    = dataprocessor.OnInvalidSkip
+	OnInvalidDefault = dataprocessor.OnInvalidDefault
+)
+
+type Rates struct {
+	// Date is the zero time when the feed carries none.
+	Date       time.Time
+	Base       string
+	Currencies []Currency
+}
+
+type DecodeOptions struct {
+	// Format is one of the Format* input constants; empty means auto.
+	Format string
+	// Encoding overrides the detected text encoding, e.g. windows-1251.
+	Encoding string
+	// OnInvalid is the policy for records that fail validation or do not
+	// parse; empty means fail.
+	OnInvalid string
+	// Validation tunes the schema checks; the zero value is the default
+	// the service applies.
+	Validation Validation
+}
+
+// Decode reads a feed in any supported format, failing on the first
+// invalid record.
+func Decode(ctx context.Context, reader io.Reader) (Rates, error) {
+	return DecodeWith(ctx, reader, DecodeOptions{
+		Format:     FormatAuto,
+		Encoding:   "",
+		OnInvalid:  OnInvalidFail,
+		Validation: Validation{MinRecords: nil, Required: nil, AllowNonISO: false},
+	})
+}
+
+func DecodeWith(ctx context.Context, reader io.Reader, options DecodeOptions) (Rates, error) {
+	result := Rates{Date: time.Time{}, Base: "", Currencies: nil}
+
+	records, err := validator.Decode(reader, validator.Options{
+		Format:    options.Format,
+		Encoding:  options.Encoding,
+		Rules:     options.Validation,
+		OnInvalid: options.OnInvalid,
+		Reject:    nil,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	defer func() { _ = records.Close() }()
+
+	list := currencyhandler.CurrencyList{
+		XMLName: xml.Name{Space: "", Local: ""},
+		Date:    "",
+		Name:    "",
+		Base:    records.Base(),
+		Items:   nil,
+	}
+
+	for records.Next() {
+		if err := ctx.Err(); err != nil {
+			return result, apperrors.Wrap(apperrors.ErrInput, err)
+		}
+
+		if len(list.Items) == 0 {
+			list.Date = records.Date()
+		}
+
+		list.Items = append(list.Items, records.Item())
+	}
+
+	if err := records.Err(); err != nil {
+		return result, err
+	}
+
+	result.Base = list.BaseCurrency()
+
+	if strings.TrimSpace(list.Date) != "" {
+		result.Date, err = list.ParseDate()
+		if err != nil {
+			return result, err
+		}
+	}
+
+	conversion, err := dataprocessor.ConvertToJSON(list, options.OnInvalid)
+	if err != nil {
+		return result, err
+	}
+
+	result.Currencies = conversion.Currencies
+
+	return result, nil
+}
+
+// Sort returns a copy ordered by keys, or by unit rate descending when
+// no keys are given.
+func (r Rates) Sort(keys ...SortKey) (Rates, error) {
+	sorted := r
+	sorted.Currencies = slices.Clone(r.Currencies)
+
+	if err := dataprocessor.SortCurrencies(sorted.Currencies, keys); err != nil {
+		return r, err
+	}
+
+	return sorted, nil
+}
+
+func (r Rates) Filter(filter Filter) (Rates, error) {
+	kept, err := filter.Apply(r.Currencies)
+	if err != nil {
+		return r, err
+	}
+
+	filtered := r
+	filtered.Currencies = kept
+
+	return filtered, nil
+}
+
+// Encode writes every field of the currencies in one of the Format*
+// output formats.
+func (r Rates) Encode(out io.Writer, format string) error {
+	return r.EncodeFields(out, format, Projection{Fields: nil, Rename: nil})
+}
+
+// EncodeFields is Encode limited to the projected fields, with keys
+// renamed as the projection says.
+func (r Rates) EncodeFields(out io.Writer, format string, projection Projection) error {
+	outputWriter, err := writer.New(format)
+	if err != nil {
+		return err
+	}
+
+	table, err := projection.Table(r.Currencies)
+	if err != nil {
+		return err
+	}
+
+	if err := outputWriter.Write(out, table); err != nil {
+		return apperrors.Wrap(apperrors.ErrWrite, fmt.Errorf("encode %s: %w", format, err))
+	}
+
+	return nil
+}
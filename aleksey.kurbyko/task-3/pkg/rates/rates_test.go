@@ -0,0 +1,73 @@
+package rates_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aleksey.kurbyko/task-3/pkg/rates"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := rates.Decode(ctx, strings.NewReader(feed))
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, err, rates.ErrInput)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := rates.Decode(context.Background(), strings.NewReader("not a feed"))
+	require.ErrorIs(t, err, rates.ErrDecode)
+
+	broken := strings.Replace(feed, "90,5567", "n/a", 1)
+
+	_, err = rates.Decode(context.Background(), strings.NewReader(broken))
+	require.ErrorIs(t, err, rates.ErrValidate)
+
+	misspelled := strings.ReplaceAll(feed, "Valute", "Valuta")
+
+	_, err = rates.Decode(context.Background(), strings.NewReader(misspelled))
+	require.ErrorIs(t, err, rates.ErrValidate)
+
+	decoded, err := rates.DecodeWith(context.Background(), strings.NewReader(broken), rates.DecodeOptions{
+		Format:     rates.FormatCBRXML,
+		Encoding:   "",
+		OnInvalid:  rates.OnInvalidSkip,
+		Validation: rates.Validation{MinRecords: nil, Required: nil, AllowNonISO: false},
+	})
+	require.NoError(t, err)
+	require.Len(t, decoded.Currencies, 2)
+}
+
+func TestDecodeDate(t *testing.T) {
+	t.Parallel()
+
+	undated := strings.Replace(feed, ` Date="18.10.2026"`, "", 1)
+
+	decoded, err := rates.Decode(context.Background(), strings.NewReader(undated))
+	require.NoError(t, err)
+	require.True(t, decoded.Date.IsZero())
+	require.Len(t, decoded.Currencies, 3)
+
+	malformed := strings.Replace(feed, "18.10.2026", "18 Oct 2026", 1)
+
+	_, err = rates.Decode(context.Background(), strings.NewReader(malformed))
+	require.ErrorIs(t, err, rates.ErrDecode)
+}
+
+func TestEncodeUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	decoded, err := rates.Decode(context.Background(), strings.NewReader(feed))
+	require.NoError(t, err)
+
+	err = decoded.Encode(&strings.Builder{}, "toml")
+	require.ErrorIs(t, err, rates.ErrConfig)
+}
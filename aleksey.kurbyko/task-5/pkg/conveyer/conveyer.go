@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 
 	"golang.org/x/sync/errgroup"
@@ -13,102 +14,149 @@ var ErrNoChannel = errors.New("chan not found")
 
 const undefined = "undefined"
 
-type Conveyer struct {
+var _ Interface[string] = (*StringConveyer)(nil)
+
+type Conveyer[T any] struct {
 	size int
 
-	channels map[string]chan string
-	handlers []func(context.Context) error
+	// closed is what Recv returns once the output channel is closed.
+	closed T
+
+	channels map[string]chan T
+	handlers []boundHandler[T]
 	mu       sync.RWMutex
+
+	nodes         []node
+	sendEndpoints map[string]bool
+	recvEndpoints map[string]bool
+
+	deadLetters       chan DeadLetter[T]
+	deadLettersClosed bool
+
+	lifecycle
+}
+
+// StringConveyer is the conveyer the handlers package was first written
+// for.
+type StringConveyer = Conveyer[string]
+
+// New returns a string conveyer. Recv on a closed output yields
+// "undefined".
+func New(size int) StringConveyer {
+	return newConveyer(size, undefined)
+}
+
+// NewOf returns a conveyer for messages of type T. Recv on a closed
+// output yields the zero value of T.
+func NewOf[T any](size int) Conveyer[T] {
+	var zero T
+
+	return newConveyer(size, zero)
 }
 
-func New(size int) Conveyer {
+func newConveyer[T any](size int, closed T) Conveyer[T] {
 	if size < 0 {
 		size = 0
 	}
 
-	return Conveyer{
+	return Conveyer[T]{
 		size:     size,
-		channels: make(map[string]chan string),
-		handlers: make([]func(context.Context) error, 0),
+		closed:   closed,
+		channels: make(map[string]chan T),
+		handlers: make([]boundHandler[T], 0),
 		mu:       sync.RWMutex{},
+
+		nodes:         nil,
+		sendEndpoints: make(map[string]bool),
+		recvEndpoints: make(map[string]bool),
+
+		deadLetters:       nil,
+		deadLettersClosed: false,
+
+		lifecycle: newLifecycle(),
 	}
 }
 
-func (c *Conveyer) RegisterDecorator(
-	handlerFunc func(ctx context.Context, input chan string, output chan string) error,
+func (c *Conveyer[T]) RegisterDecorator(
+	handlerFunc func(ctx context.Context, input chan T, output chan T) error,
 	input string,
 	output string,
+	opts ...Option,
 ) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.makeChannels(input, output)
-
-	inputChannel := c.channels[input]
-	outputChannel := c.channels[output]
-
-	c.handlers = append(c.handlers, func(ctx context.Context) error {
-		return handlerFunc(ctx, inputChannel, outputChannel)
-	})
+	c.register("decorator", []string{input}, []string{output}, opts,
+		func(ctx context.Context, inputs []chan T, outputs []chan T) error {
+			return handlerFunc(ctx, inputs[0], outputs[0])
+		})
 }
 
-func (c *Conveyer) RegisterMultiplexer(
-	handlerFunc func(ctx context.Context, inputs []chan string, output chan string) error,
+func (c *Conveyer[T]) RegisterMultiplexer(
+	handlerFunc func(ctx context.Context, inputs []chan T, output chan T) error,
 	inputs []string,
 	output string,
+	opts ...Option,
 ) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.makeChannels(output)
-	c.makeChannels(inputs...)
-
-	inputChannels := make([]chan string, 0, len(inputs))
-	for _, name := range inputs {
-		inputChannels = append(inputChannels, c.channels[name])
-	}
-
-	outputChannel := c.channels[output]
-
-	c.handlers = append(c.handlers, func(ctx context.Context) error {
-		return handlerFunc(ctx, inputChannels, outputChannel)
-	})
+	c.register("multiplexer", inputs, []string{output}, opts,
+		func(ctx context.Context, inputs []chan T, outputs []chan T) error {
+			return handlerFunc(ctx, inputs, outputs[0])
+		})
 }
 
-func (c *Conveyer) RegisterSeparator(
-	handlerFunc func(ctx context.Context, input chan string, outputs []chan string) error,
+func (c *Conveyer[T]) RegisterSeparator(
+	handlerFunc func(ctx context.Context, input chan T, outputs []chan T) error,
 	input string,
 	outputs []string,
+	opts ...Option,
 ) {
+	c.register("separator", []string{input}, outputs, opts,
+		func(ctx context.Context, inputs []chan T, outputs []chan T) error {
+			return handlerFunc(ctx, inputs[0], outputs)
+		})
+}
+
+// boundHandler is a registered handler adapted to take its channels as
+// slices, so that Run can hand it tapped inputs.
+type boundHandler[T any] func(ctx context.Context, inputs []chan T, outputs []chan T) error
+
+func (c *Conveyer[T]) register(kind string, inputs, outputs []string, opts []Option, handler boundHandler[T]) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.makeChannels(input)
+	settings := newOptions(kind, inputs, outputs, opts)
+
+	c.makeChannels(inputs...)
 	c.makeChannels(outputs...)
 
-	inputChannel := c.channels[input]
+	c.nodes = append(c.nodes, node{
+		inputs:   slices.Clone(inputs),
+		outputs:  slices.Clone(outputs),
+		options:  settings,
+		counters: new(counters),
+	})
+	c.handlers = append(c.handlers, handler)
+}
 
-	outputChannels := make([]chan string, 0, len(outputs))
-	for _, name := range outputs {
-		outputChannels = append(outputChannels, c.channels[name])
+func (c *Conveyer[T]) Run(ctx context.Context) error {
+	runCtx, err := c.start(ctx)
+	if err != nil {
+		return fmt.Errorf("conveyer run: %w", err)
 	}
 
-	c.handlers = append(c.handlers, func(ctx context.Context) error {
-		return handlerFunc(ctx, inputChannel, outputChannels)
-	})
-}
+	defer c.finish()
 
-func (c *Conveyer) Run(ctx context.Context) error {
-	defer c.closeAllChannels()
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("conveyer run: %w", err)
+	}
 
-	group, groupCtx := errgroup.WithContext(ctx)
+	group, groupCtx := errgroup.WithContext(runCtx)
 
 	handlers := c.snapshotHandlers()
-	for _, handlerFunc := range handlers {
-		currentHandler := handlerFunc
+	for index, handlerFunc := range handlers {
+		currentIndex, currentHandler := index, handlerFunc
 
 		task := func() error {
-			return currentHandler(groupCtx)
+			defer c.handlerDone(currentIndex)
+
+			return c.supervise(groupCtx, currentIndex, currentHandler)
 		}
 
 		group.Go(task)
@@ -121,66 +169,96 @@ func (c *Conveyer) Run(ctx context.Context) error {
 	return nil
 }
 
-func (c *Conveyer) Send(input string, data string) error {
+// Send blocks until the message is taken or the conveyer stops accepting
+// input, in which case it returns a *ClosedError.
+func (c *Conveyer[T]) Send(input string, data T) error {
 	c.mu.RLock()
 	channel, ok := c.channels[input]
+	accepting := ok && c.acceptSend()
 	c.mu.RUnlock()
 
 	if !ok {
 		return ErrNoChannel
 	}
 
-	channel <- data
+	if !accepting {
+		return &ClosedError{Op: "send", Channel: input}
+	}
+
+	defer c.sending.Done()
 
-	return nil
+	select {
+	case channel <- data:
+		return nil
+	case <-c.stopping:
+		return &ClosedError{Op: "send", Channel: input}
+	}
 }
 
-func (c *Conveyer) Recv(output string) (string, error) {
+// Recv returns the closed value once output is closed and drained, and a
+// *ClosedError as well if that happened through Shutdown.
+func (c *Conveyer[T]) Recv(output string) (T, error) {
 	c.mu.RLock()
 	channel, ok := c.channels[output]
 	c.mu.RUnlock()
 
 	if !ok {
-		return "", ErrNoChannel
+		var zero T
+
+		return zero, ErrNoChannel
 	}
 
 	data, opened := <-channel
 	if !opened {
-		return undefined, nil
+		if c.shutdown.Load() {
+			return c.closed, &ClosedError{Op: "recv", Channel: output}
+		}
+
+		return c.closed, nil
 	}
 
 	return data, nil
 }
 
-func (c *Conveyer) snapshotHandlers() []func(context.Context) error {
+func (c *Conveyer[T]) snapshotHandlers() []boundHandler[T] {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	handlers := make([]func(context.Context) error, len(c.handlers))
+	handlers := make([]boundHandler[T], len(c.handlers))
 	copy(handlers, c.handlers)
 
 	return handlers
 }
 
-func (c *Conveyer) makeChannel(name string) {
+func (c *Conveyer[T]) makeChannel(name string) {
 	if _, ok := c.channels[name]; ok {
 		return
 	}
 
-	c.channels[name] = make(chan string, c.size)
+	c.channels[name] = make(chan T, c.size)
 }
 
-func (c *Conveyer) makeChannels(names ...string) {
+func (c *Conveyer[T]) makeChannels(names ...string) {
 	for _, name := range names {
 		c.makeChannel(name)
 	}
 }
 
-func (c *Conveyer) closeAllChannels() {
+func (c *Conveyer[T]) closeAllChannels() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	for _, channel := range c.channels {
-		close(channel)
+	for name := range c.channels {
+		c.closeChannel(name)
+	}
+}
+
+// closeChannel must be called with mu held.
+func (c *Conveyer[T]) closeChannel(name string) {
+	if c.closedChannels[name] {
+		return
 	}
+
+	c.closedChannels[name] = true
+	close(c.channels[name])
 }
@@ -0,0 +1,120 @@
+package conveyer_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"aleksey.kurbyko/task-5/pkg/conveyer"
+	"aleksey.kurbyko/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+type order struct {
+	ID     int
+	Amount int
+}
+
+// runConveyer starts Run and returns a function that shuts the conveyer
+// down and reports what Run returned.
+func runConveyer[T any](t *testing.T, pipeline *conveyer.Conveyer[T]) func() error {
+	t.Helper()
+
+	result := make(chan error, 1)
+
+	go func() {
+		result <- pipeline.Run(context.Background())
+	}()
+
+	return func() error {
+		require.NoError(t, pipeline.Shutdown(context.Background()))
+
+		return <-result
+	}
+}
+
+func TestStringConveyer(t *testing.T) {
+	t.Parallel()
+
+	pipeline := conveyer.New(1)
+	pipeline.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "decorated")
+	pipeline.RegisterSeparator(handlers.SeparatorFunc, "decorated", []string{"left", "right"})
+	pipeline.RegisterMultiplexer(handlers.MultiplexerFunc, []string{"left", "right"}, "out")
+
+	stop := runConveyer(t, &pipeline)
+
+	for _, message := range []string{"a", "b", "c"} {
+		require.NoError(t, pipeline.Send("in", message))
+	}
+
+	received := make([]string, 0, 3)
+
+	for range 3 {
+		message, err := pipeline.Recv("out")
+		require.NoError(t, err)
+
+		received = append(received, message)
+	}
+
+	require.ElementsMatch(t, []string{"decorated: a", "decorated: b", "decorated: c"}, received)
+	require.NoError(t, stop())
+
+	message, err := pipeline.Recv("out")
+	require.ErrorIs(t, err, conveyer.ErrClosed)
+	require.Equal(t, "undefined", message)
+}
+
+func TestTypedConveyer(t *testing.T) {
+	t.Parallel()
+
+	pipeline := conveyer.NewOf[order](0)
+	pipeline.RegisterDecorator(handlers.Decorator(func(message order) (order, error) {
+		message.Amount *= 2
+
+		return message, nil
+	}), "orders", "doubled")
+
+	stop := runConveyer(t, &pipeline)
+
+	require.NoError(t, pipeline.Send("orders", order{ID: 1, Amount: 21}))
+
+	doubled, err := pipeline.Recv("doubled")
+	require.NoError(t, err)
+	require.Equal(t, order{ID: 1, Amount: 42}, doubled)
+	require.NoError(t, stop())
+
+	closed, err := pipeline.Recv("doubled")
+	require.ErrorIs(t, err, conveyer.ErrClosed)
+	require.Zero(t, closed)
+}
+
+func TestUnknownChannel(t *testing.T) {
+	t.Parallel()
+
+	pipeline := conveyer.New(0)
+
+	require.ErrorIs(t, pipeline.Send("missing", "a"), conveyer.ErrNoChannel)
+
+	_, err := pipeline.Recv("missing")
+	require.ErrorIs(t, err, conveyer.ErrNoChannel)
+}
+
+func TestRunTwice(t *testing.T) {
+	t.Parallel()
+
+	pipeline := conveyer.New(0)
+	pipeline.RegisterDecorator(handlers.Decorator(func(message string) (string, error) {
+		return strings.ToUpper(message), nil
+	}), "in", "out")
+
+	stop := runConveyer(t, &pipeline)
+
+	require.NoError(t, pipeline.Send("in", "a"))
+
+	message, err := pipeline.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "A", message)
+
+	require.ErrorIs(t, pipeline.Run(context.Background()), conveyer.ErrAlreadyStarted)
+	require.NoError(t, stop())
+}
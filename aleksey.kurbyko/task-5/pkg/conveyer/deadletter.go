@@ -0,0 +1,115 @@
+package conveyer
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNoDeadLetters = errors.New("dead letters are not enabled")
+
+const deadLetterQueue = "dead letters"
+
+// DeadLetter is a message a handler rejected or failed on, with the
+// reason and the name of the handler (see WithName).
+type DeadLetter[T any] struct {
+	Message T
+	Reason  error
+	Handler string
+}
+
+// EnableDeadLetters gives the conveyer a dead-letter queue holding up to
+// size messages. Messages handlers reject and messages skipped under
+// SkipMessage go there, and handlers block while it is full, so read it
+// with RecvDeadLetter. Call it before Run.
+func (c *Conveyer[T]) EnableDeadLetters(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.deadLetters == nil {
+		c.deadLetters = make(chan DeadLetter[T], max(size, 0))
+	}
+}
+
+// RecvDeadLetter blocks until a rejected message is available. Once the
+// conveyer has stopped and the queue is drained it returns a *ClosedError.
+func (c *Conveyer[T]) RecvDeadLetter() (DeadLetter[T], error) {
+	c.mu.RLock()
+	queue := c.deadLetters
+	c.mu.RUnlock()
+
+	if queue == nil {
+		var none DeadLetter[T]
+
+		return none, ErrNoDeadLetters
+	}
+
+	letter, opened := <-queue
+	if !opened {
+		return letter, &ClosedError{Op: "recv", Channel: deadLetterQueue}
+	}
+
+	return letter, nil
+}
+
+// rejecter applies the policy of one handler to the messages it rejects
+// through deadletter.Reject.
+type rejecter[T any] struct {
+	registered node
+	queue      chan DeadLetter[T]
+}
+
+func (c *Conveyer[T]) rejecter(registered node) *rejecter[T] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return &rejecter[T]{registered: registered, queue: c.deadLetters}
+}
+
+// Reject lets the handler carry on under SkipMessage and Ignore. Under
+// FailFast and Restart the rejection is counted and the handler should
+// fail, so that the policy decides what happens next.
+func (r *rejecter[T]) Reject(ctx context.Context, message T, reason error) bool {
+	counters := r.registered.counters
+
+	switch r.registered.options.policy {
+	case SkipMessage:
+		counters.skipped.Add(1)
+		r.record(ctx, message, reason)
+
+		return true
+	case Ignore:
+		counters.ignored.Add(1)
+
+		return true
+	default:
+		counters.rejected.Add(1)
+		r.record(ctx, message, reason)
+
+		return false
+	}
+}
+
+// record sends message to the dead-letter queue, if enabled.
+func (r *rejecter[T]) record(ctx context.Context, message T, reason error) {
+	if r.queue == nil {
+		return
+	}
+
+	letter := DeadLetter[T]{Message: message, Reason: reason, Handler: r.registered.options.name}
+
+	select {
+	case <-ctx.Done():
+	case r.queue <- letter:
+		r.registered.counters.deadLettered.Add(1)
+	}
+}
+
+func (c *Conveyer[T]) closeDeadLetters() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.deadLetters != nil && !c.deadLettersClosed {
+		c.deadLettersClosed = true
+		close(c.deadLetters)
+	}
+}
@@ -0,0 +1,117 @@
+package conveyer_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aleksey.kurbyko/task-5/pkg/conveyer"
+	"aleksey.kurbyko/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLettersSkip(t *testing.T) {
+	t.Parallel()
+
+	pipeline := conveyer.New(3)
+	pipeline.EnableDeadLetters(3)
+	pipeline.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out",
+		conveyer.WithName("prefix"), conveyer.WithSkipMessage())
+
+	for _, message := range []string{"a", "no decorator", "b"} {
+		require.NoError(t, pipeline.Send("in", message))
+	}
+
+	require.NoError(t, runConveyer(t, &pipeline)())
+	require.Equal(t, []string{"decorated: a", "decorated: b"}, recvAll(t, &pipeline, "out"))
+
+	letter, err := pipeline.RecvDeadLetter()
+	require.NoError(t, err)
+	require.Equal(t, conveyer.DeadLetter[string]{
+		Message: "no decorator",
+		Reason:  handlers.ErrCantBeDecorated,
+		Handler: "prefix",
+	}, letter)
+
+	_, err = pipeline.RecvDeadLetter()
+	require.ErrorIs(t, err, conveyer.ErrClosed)
+
+	stats := pipeline.Stats()[0]
+	require.Zero(t, stats.Errors, "a rejection the policy skips is not a handler error")
+	require.Equal(t, int64(1), stats.Skipped)
+	require.Equal(t, int64(1), stats.DeadLettered)
+}
+
+func TestDeadLettersFailFast(t *testing.T) {
+	t.Parallel()
+
+	pipeline := conveyer.New(2)
+	pipeline.EnableDeadLetters(1)
+	pipeline.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+
+	require.NoError(t, pipeline.Send("in", "no decorator"))
+	require.ErrorIs(t, pipeline.Run(context.Background()), handlers.ErrCantBeDecorated)
+
+	letter, err := pipeline.RecvDeadLetter()
+	require.NoError(t, err)
+	require.Equal(t, "no decorator", letter.Message)
+
+	stats := pipeline.Stats()[0]
+	require.Equal(t, int64(1), stats.Rejected)
+	require.Equal(t, int64(1), stats.DeadLettered)
+	require.Equal(t, int64(1), stats.Errors)
+}
+
+func TestDeadLettersRestart(t *testing.T) {
+	t.Parallel()
+
+	pipeline := conveyer.New(2)
+	pipeline.EnableDeadLetters(1)
+	pipeline.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out",
+		conveyer.WithRestart(time.Millisecond, time.Millisecond))
+
+	require.NoError(t, pipeline.Send("in", "no decorator"))
+	require.NoError(t, pipeline.Send("in", "a"))
+	require.NoError(t, runConveyer(t, &pipeline)())
+	require.Equal(t, []string{"decorated: a"}, recvAll(t, &pipeline, "out"))
+
+	letter, err := pipeline.RecvDeadLetter()
+	require.NoError(t, err)
+	require.Equal(t, "no decorator", letter.Message, "restart does not lose the rejected message")
+
+	stats := pipeline.Stats()[0]
+	require.Equal(t, int64(1), stats.Rejected)
+	require.Equal(t, int64(1), stats.Restarts)
+}
+
+func TestMultiplexerRejectsAreCounted(t *testing.T) {
+	t.Parallel()
+
+	pipeline := conveyer.New(2)
+	pipeline.RegisterMultiplexer(handlers.MultiplexerFunc, []string{"left", "right"}, "out")
+
+	require.NoError(t, pipeline.Send("left", "a"))
+	require.NoError(t, pipeline.Send("right", "no multiplexer"))
+	require.NoError(t, runConveyer(t, &pipeline)())
+	require.Equal(t, []string{"a"}, recvAll(t, &pipeline, "out"))
+	require.Equal(t, int64(1), pipeline.Stats()[0].Rejected, "dead letters are off but the drop is counted")
+
+	_, err := pipeline.RecvDeadLetter()
+	require.ErrorIs(t, err, conveyer.ErrNoDeadLetters)
+}
+
+func TestMultiplexerIgnore(t *testing.T) {
+	t.Parallel()
+
+	pipeline := conveyer.New(2)
+	pipeline.EnableDeadLetters(1)
+	pipeline.RegisterMultiplexer(handlers.MultiplexerFunc, []string{"left", "right"}, "out", conveyer.WithIgnore())
+
+	require.NoError(t, pipeline.Send("left", "no multiplexer"))
+	require.NoError(t, runConveyer(t, &pipeline)())
+	require.Empty(t, recvAll(t, &pipeline, "out"))
+
+	_, err := pipeline.RecvDeadLetter()
+	require.ErrorIs(t, err, conveyer.ErrClosed, "ignored messages are not dead-lettered")
+	require.Equal(t, int64(1), pipeline.Stats()[0].Ignored)
+}
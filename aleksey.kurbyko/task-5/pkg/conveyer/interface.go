@@ -0,0 +1,41 @@
+package conveyer
+
+import "context"
+
+// Interface is the full conveyer contract for messages of type T.
+type Interface[T any] interface {
+	RegisterDecorator(
+		handlerFunc func(ctx context.Context, input chan T, output chan T) error,
+		input string,
+		output string,
+		opts ...Option,
+	)
+
+	RegisterMultiplexer(
+		handlerFunc func(ctx context.Context, inputs []chan T, output chan T) error,
+		inputs []string,
+		output string,
+		opts ...Option,
+	)
+
+	RegisterSeparator(
+		handlerFunc func(ctx context.Context, input chan T, outputs []chan T) error,
+		input string,
+		outputs []string,
+		opts ...Option,
+	)
+
+	DeclareInputs(names ...string)
+	DeclareOutputs(names ...string)
+	Validate() error
+
+	EnableDeadLetters(size int)
+	RecvDeadLetter() (DeadLetter[T], error)
+
+	Run(ctx context.Context) error
+	Send(input string, data T) error
+	Recv(output string) (T, error)
+	Shutdown(ctx context.Context) error
+
+	Stats() []HandlerStats
+}
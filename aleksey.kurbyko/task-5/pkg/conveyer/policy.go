@@ -0,0 +1,257 @@
+package conveyer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"aleksey.kurbyko/task-5/pkg/deadletter"
+)
+
+// Policy decides what happens when a handler returns an error.
+type Policy int
+
+const (
+	// FailFast stops the whole conveyer with the error. It is the default.
+	FailFast Policy = iota
+	// Restart runs the handler again after an exponential backoff. The
+	// message it failed on is lost unless the handler rejects it.
+	Restart
+	// SkipMessage drops the message the handler failed on, sending it to
+	// the dead-letter queue if enabled, and runs the handler again.
+	SkipMessage
+	// Ignore drops the failed message and runs the handler again.
+	Ignore
+)
+
+const (
+	defaultInitialBackoff = 10 * time.Millisecond
+	defaultMaxBackoff     = time.Second
+	backoffFactor         = 2
+)
+
+func (p Policy) String() string {
+	switch p {
+	case FailFast:
+		return "fail-fast"
+	case Restart:
+		return "restart"
+	case SkipMessage:
+		return "skip-message"
+	case Ignore:
+		return "ignore"
+	default:
+		return fmt.Sprintf("policy(%d)", int(p))
+	}
+}
+
+type options struct {
+	name           string
+	policy         Policy
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// Option configures one handler registration.
+type Option func(*options)
+
+// WithName sets the name the handler is reported under in Stats.
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+func WithFailFast() Option {
+	return func(o *options) {
+		o.policy = FailFast
+	}
+}
+
+// WithRestart restarts a failed handler, waiting initial before the first
+// restart and doubling the wait up to maximum. Non-positive values keep
+// the defaults of 10ms and 1s.
+func WithRestart(initial, maximum time.Duration) Option {
+	return func(o *options) {
+		o.policy = Restart
+
+		if initial > 0 {
+			o.initialBackoff = initial
+		}
+
+		if maximum > 0 {
+			o.maxBackoff = max(maximum, o.initialBackoff)
+		}
+	}
+}
+
+// WithSkipMessage skips the message a handler failed on or rejected. It
+// goes to the dead-letter queue, if enabled, with the error as the
+// reason.
+func WithSkipMessage() Option {
+	return func(o *options) {
+		o.policy = SkipMessage
+	}
+}
+
+func WithIgnore() Option {
+	return func(o *options) {
+		o.policy = Ignore
+	}
+}
+
+func newOptions(kind string, inputs, outputs []string, opts []Option) options {
+	settings := options{
+		name:           fmt.Sprintf("%s %s -> %s", kind, strings.Join(inputs, ","), strings.Join(outputs, ",")),
+		policy:         FailFast,
+		initialBackoff: defaultInitialBackoff,
+		maxBackoff:     defaultMaxBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	return settings
+}
+
+type counters struct {
+	errors       atomic.Int64
+	restarts     atomic.Int64
+	rejected     atomic.Int64
+	skipped      atomic.Int64
+	deadLettered atomic.Int64
+	ignored      atomic.Int64
+}
+
+// HandlerStats reports how a handler's error policy has been applied.
+// Rejected counts the messages rejected under fail-fast or restart.
+type HandlerStats struct {
+	Name         string
+	Policy       Policy
+	Errors       int64
+	Restarts     int64
+	Rejected     int64
+	Skipped      int64
+	DeadLettered int64
+	Ignored      int64
+}
+
+// Stats returns the counters of every handler in registration order.
+func (c *Conveyer[T]) Stats() []HandlerStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := make([]HandlerStats, 0, len(c.nodes))
+
+	for _, registered := range c.nodes {
+		stats = append(stats, HandlerStats{
+			Name:         registered.options.name,
+			Policy:       registered.options.policy,
+			Errors:       registered.counters.errors.Load(),
+			Restarts:     registered.counters.restarts.Load(),
+			Rejected:     registered.counters.rejected.Load(),
+			Skipped:      registered.counters.skipped.Load(),
+			DeadLettered: registered.counters.deadLettered.Load(),
+			Ignored:      registered.counters.ignored.Load(),
+		})
+	}
+
+	return stats
+}
+
+// supervise runs one handler under its error policy. Skip and ignore need
+// to know which message failed, so the handler reaches its channels
+// through a tap; if it fails without having taken a message since it
+// last emitted one or failed, there is nothing to drop and the error
+// stops the conveyer.
+func (c *Conveyer[T]) supervise(ctx context.Context, index int, handler boundHandler[T]) error {
+	c.mu.RLock()
+	registered := c.nodes[index]
+	inputs := c.lookup(registered.inputs)
+	outputs := c.lookup(registered.outputs)
+	c.mu.RUnlock()
+
+	settings := registered.options
+	rejects := c.rejecter(registered)
+	ctx = deadletter.NewContext[T](ctx, rejects)
+
+	var messages *tap[T]
+	if settings.policy == SkipMessage || settings.policy == Ignore {
+		messages = newTap(inputs, outputs)
+		inputs, outputs = messages.handlerInputs, messages.handlerOutputs
+
+		go messages.run(ctx)
+		defer messages.stop()
+	}
+
+	backoff := settings.initialBackoff
+
+	for {
+		err := handler(ctx, inputs, outputs)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		registered.counters.errors.Add(1)
+
+		switch settings.policy {
+		case Restart:
+			registered.counters.restarts.Add(1)
+
+			if !sleep(ctx, backoff) {
+				return nil
+			}
+
+			backoff = min(backoff*backoffFactor, settings.maxBackoff)
+		case SkipMessage, Ignore:
+			message, taken := messages.last(ctx)
+			if !taken {
+				return fmt.Errorf("%s: %w", settings.name, err)
+			}
+
+			if settings.policy == Ignore {
+				registered.counters.ignored.Add(1)
+
+				continue
+			}
+
+			registered.counters.skipped.Add(1)
+			rejects.record(ctx, message, err)
+		default:
+			return err
+		}
+	}
+}
+
+func (c *Conveyer[T]) lookup(names []string) []chan T {
+	channels := make([]chan T, 0, len(names))
+	for _, name := range names {
+		channels = append(channels, c.channels[name])
+	}
+
+	return channels
+}
+
+func sleep(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func sendContext[T any](ctx context.Context, channel chan T, message T) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case channel <- message:
+		return true
+	}
+}
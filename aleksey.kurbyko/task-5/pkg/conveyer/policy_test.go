@@ -0,0 +1,189 @@
+package conveyer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"aleksey.kurbyko/task-5/pkg/conveyer"
+	"github.com/stretchr/testify/require"
+)
+
+var errBroken = errors.New("broken")
+
+// failOn returns a decorator that fails on the given message and
+// forwards the others unchanged.
+func failOn(bad string) func(ctx context.Context, input chan string, output chan string) error {
+	return func(ctx context.Context, input chan string, output chan string) error {
+		for message := range input {
+			if message == bad {
+				return errBroken
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case output <- message:
+			}
+		}
+
+		return nil
+	}
+}
+
+func recvAll(t *testing.T, pipeline *conveyer.StringConveyer, output string) []string {
+	t.Helper()
+
+	var received []string
+
+	for {
+		message, err := pipeline.Recv(output)
+		if err != nil {
+			require.ErrorIs(t, err, conveyer.ErrClosed)
+
+			return received
+		}
+
+		received = append(received, message)
+	}
+}
+
+func TestFailFast(t *testing.T) {
+	t.Parallel()
+
+	pipeline := conveyer.New(3)
+	pipeline.RegisterDecorator(failOn("b"), "in", "out", conveyer.WithFailFast())
+
+	for _, message := range []string{"a", "b", "c"} {
+		require.NoError(t, pipeline.Send("in", message))
+	}
+
+	require.ErrorIs(t, pipeline.Run(context.Background()), errBroken)
+	require.Equal(t, int64(1), pipeline.Stats()[0].Errors)
+}
+
+func TestSkipMessage(t *testing.T) {
+	t.Parallel()
+
+	pipeline := conveyer.New(3)
+	pipeline.EnableDeadLetters(1)
+	pipeline.RegisterDecorator(failOn("b"), "in", "out", conveyer.WithName("check"), conveyer.WithSkipMessage())
+
+	for _, message := range []string{"a", "b", "c"} {
+		require.NoError(t, pipeline.Send("in", message))
+	}
+
+	require.NoError(t, runConveyer(t, &pipeline)())
+	require.Equal(t, []string{"a", "c"}, recvAll(t, &pipeline, "out"))
+
+	letter, err := pipeline.RecvDeadLetter()
+	require.NoError(t, err)
+	require.Equal(t, conveyer.DeadLetter[string]{Message: "b", Reason: errBroken, Handler: "check"}, letter)
+
+	require.Equal(t, []conveyer.HandlerStats{{
+		Name:         "check",
+		Policy:       conveyer.SkipMessage,
+		Errors:       1,
+		Restarts:     0,
+		Rejected:     0,
+		Skipped:      1,
+		DeadLettered: 1,
+		Ignored:      0,
+	}}, pipeline.Stats())
+}
+
+func TestIgnore(t *testing.T) {
+	t.Parallel()
+
+	pipeline := conveyer.New(3)
+	pipeline.RegisterDecorator(failOn("b"), "in", "out", conveyer.WithIgnore())
+
+	for _, message := range []string{"a", "b", "c"} {
+		require.NoError(t, pipeline.Send("in", message))
+	}
+
+	require.NoError(t, runConveyer(t, &pipeline)())
+	require.Equal(t, []string{"a", "c"}, recvAll(t, &pipeline, "out"))
+
+	stats := pipeline.Stats()[0]
+	require.Equal(t, int64(1), stats.Ignored)
+	require.Zero(t, stats.Skipped)
+	require.Equal(t, "decorator in -> out", stats.Name)
+}
+
+func TestRestart(t *testing.T) {
+	t.Parallel()
+
+	failures := 2
+	handler := func(ctx context.Context, input chan string, output chan string) error {
+		if failures > 0 {
+			failures--
+
+			return errBroken
+		}
+
+		return failOn("")(ctx, input, output)
+	}
+
+	pipeline := conveyer.New(2)
+	pipeline.RegisterDecorator(handler, "in", "out", conveyer.WithRestart(time.Millisecond, 2*time.Millisecond))
+
+	require.NoError(t, pipeline.Send("in", "a"))
+	require.NoError(t, pipeline.Send("in", "b"))
+	require.NoError(t, runConveyer(t, &pipeline)())
+	require.Equal(t, []string{"a", "b"}, recvAll(t, &pipeline, "out"))
+
+	stats := pipeline.Stats()[0]
+	require.Equal(t, int64(2), stats.Errors)
+	require.Equal(t, int64(2), stats.Restarts)
+}
+
+func TestSkipBlamesOnlyUnsettledMessages(t *testing.T) {
+	t.Parallel()
+
+	// The handler forwards its message and only then fails, so there is
+	// nothing left to skip and the error must stop the conveyer.
+	handler := func(ctx context.Context, input chan string, output chan string) error {
+		message := <-input
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case output <- message:
+		}
+
+		return errBroken
+	}
+
+	pipeline := conveyer.New(1)
+	pipeline.EnableDeadLetters(1)
+	pipeline.RegisterDecorator(handler, "in", "out", conveyer.WithSkipMessage())
+
+	require.NoError(t, pipeline.Send("in", "a"))
+
+	result := make(chan error, 1)
+
+	go func() {
+		result <- pipeline.Run(context.Background())
+	}()
+
+	message, err := pipeline.Recv("out")
+	require.NoError(t, err)
+	require.Equal(t, "a", message)
+	require.ErrorIs(t, <-result, errBroken)
+
+	stats := pipeline.Stats()[0]
+	require.Zero(t, stats.Skipped)
+	require.Zero(t, stats.DeadLettered)
+}
+
+func TestPolicyString(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "fail-fast", conveyer.FailFast.String())
+	require.Equal(t, "restart", conveyer.Restart.String())
+	require.Equal(t, "skip-message", conveyer.SkipMessage.String())
+	require.Equal(t, "ignore", conveyer.Ignore.String())
+	require.Equal(t, "policy(9)", conveyer.Policy(9).String())
+}
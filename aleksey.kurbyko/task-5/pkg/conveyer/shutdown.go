@@ -0,0 +1,263 @@
+package conveyer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	ErrClosed         = errors.New("conveyer is closed")
+	ErrAlreadyStarted = errors.New("conveyer is already running")
+	ErrDiscarded      = errors.New("messages discarded")
+)
+
+// ClosedError is returned by Send once the conveyer stops accepting input
+// and by Recv on a drained output after Shutdown.
+type ClosedError struct {
+	Op      string
+	Channel string
+}
+
+func (e *ClosedError) Error() string {
+	return fmt.Sprintf("%s %q: %s", e.Op, e.Channel, ErrClosed)
+}
+
+func (e *ClosedError) Unwrap() error {
+	return ErrClosed
+}
+
+// DiscardedError is returned by Shutdown when ctx ends before Run starts
+// while Send has left messages in the channels.
+type DiscardedError struct {
+	Pending int
+	Err     error
+}
+
+func (e *DiscardedError) Error() string {
+	return fmt.Sprintf("conveyer shutdown: %d %s before run: %v", e.Pending, ErrDiscarded, e.Err)
+}
+
+func (e *DiscardedError) Unwrap() []error {
+	return []error{ErrDiscarded, e.Err}
+}
+
+type lifecycle struct {
+	started bool
+	begun   chan struct{}
+	// abandoned is set when Shutdown closed the conveyer before Run.
+	abandoned bool
+	cancel    context.CancelFunc
+	done      chan struct{}
+	stopping  chan struct{}
+	stopOnce  sync.Once
+	sending   sync.WaitGroup
+	shutdown  atomic.Bool
+
+	closedChannels map[string]bool
+	// producers counts the running handlers that write to each channel;
+	// a channel is closed when its count drops to zero.
+	producers map[string]int
+}
+
+func newLifecycle() lifecycle {
+	return lifecycle{
+		started:        false,
+		begun:          make(chan struct{}),
+		abandoned:      false,
+		cancel:         nil,
+		done:           make(chan struct{}),
+		stopping:       make(chan struct{}),
+		stopOnce:       sync.Once{},
+		sending:        sync.WaitGroup{},
+		shutdown:       atomic.Bool{},
+		closedChannels: make(map[string]bool),
+		producers:      make(map[string]int),
+	}
+}
+
+// Shutdown stops accepting Send, closes the channels nobody but Send
+// writes to and lets the handlers drain: each handler exits once its
+// inputs are closed and empty, and a channel is closed only after every
+// handler writing to it has exited. Messages already in flight reach the
+// outputs, where Recv can still read them. If ctx ends first the handlers
+// are cancelled and the ctx error is returned.
+//
+// Before Run, Shutdown closes the conveyer at once if Send left nothing
+// in the channels. Otherwise it waits for Run to start and drain them,
+// and returns a *DiscardedError if ctx ends first.
+func (c *Conveyer[T]) Shutdown(ctx context.Context) error {
+	c.shutdown.Store(true)
+	c.stopSends()
+
+	if running, err := c.awaitStart(ctx); !running {
+		return err
+	}
+
+	c.mu.Lock()
+
+	done, cancel := c.done, c.cancel
+
+	for name := range c.channels {
+		if c.producers[name] == 0 {
+			c.closeChannel(name)
+		}
+	}
+
+	c.mu.Unlock()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		cancel()
+		<-done
+
+		return fmt.Errorf("conveyer shutdown: %w", ctx.Err())
+	}
+}
+
+// awaitStart reports whether Run has started, waiting for it while Send
+// has left messages behind. Otherwise it closes the conveyer.
+func (c *Conveyer[T]) awaitStart(ctx context.Context) (bool, error) {
+	c.mu.RLock()
+	started, abandoned, pending := c.started, c.abandoned, c.pending()
+	c.mu.RUnlock()
+
+	if abandoned {
+		return false, nil
+	}
+
+	if !started && pending > 0 {
+		select {
+		case <-c.begun:
+			return true, nil
+		case <-ctx.Done():
+		}
+	}
+
+	if c.abandon() {
+		return true, nil
+	}
+
+	if pending == 0 {
+		return false, nil
+	}
+
+	return false, &DiscardedError{Pending: pending, Err: ctx.Err()}
+}
+
+// abandon closes a conveyer that never ran and reports false, or reports
+// true if Run has started after all.
+func (c *Conveyer[T]) abandon() bool {
+	c.mu.Lock()
+
+	if c.started {
+		c.mu.Unlock()
+
+		return true
+	}
+
+	c.abandoned = true
+
+	for name := range c.channels {
+		c.closeChannel(name)
+	}
+
+	c.mu.Unlock()
+
+	c.closeDeadLetters()
+
+	return false
+}
+
+// pending counts the messages waiting in the channels; it must be called
+// with mu held.
+func (c *Conveyer[T]) pending() int {
+	count := 0
+	for _, channel := range c.channels {
+		count += len(channel)
+	}
+
+	return count
+}
+
+func (c *Conveyer[T]) start(ctx context.Context) (context.Context, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.started {
+		return ctx, ErrAlreadyStarted
+	}
+
+	if c.abandoned {
+		return ctx, ErrClosed
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	c.started, c.cancel = true, cancel
+
+	for _, registered := range c.nodes {
+		for _, output := range registered.outputs {
+			c.producers[output]++
+		}
+	}
+
+	close(c.begun)
+
+	return runCtx, nil
+}
+
+func (c *Conveyer[T]) finish() {
+	c.cancel()
+	c.stopSends()
+	c.closeAllChannels()
+	c.closeDeadLetters()
+	close(c.done)
+}
+
+func (c *Conveyer[T]) handlerDone(index int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, output := range c.nodes[index].outputs {
+		c.producers[output]--
+
+		if c.producers[output] == 0 {
+			c.closeChannel(output)
+		}
+	}
+}
+
+// acceptSend registers an in-flight Send; it must be called with mu held
+// so that stopSends cannot start waiting in between.
+func (c *Conveyer[T]) acceptSend() bool {
+	if c.stopped() {
+		return false
+	}
+
+	c.sending.Add(1)
+
+	return true
+}
+
+func (c *Conveyer[T]) stopped() bool {
+	select {
+	case <-c.stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+// stopSends makes new Sends fail, releases the blocked ones and waits for
+// them to leave, so that no channel is closed under a pending Send.
+func (c *Conveyer[T]) stopSends() {
+	c.mu.Lock()
+	c.stopOnce.Do(func() { close(c.stopping) })
+	c.mu.Unlock()
+
+	c.sending.Wait()
+}
@@ -0,0 +1,195 @@
+package conveyer_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"aleksey.kurbyko/task-5/pkg/conveyer"
+	"aleksey.kurbyko/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+const messages = 10
+
+// gatedChain is in -> a -> b -> out with decorators that hold every
+// message until release is closed. The first message a decorator takes
+// is signalled on held.
+func gatedChain(release chan struct{}) (*conveyer.StringConveyer, chan struct{}) {
+	held := make(chan struct{}, 1)
+	gated := handlers.Decorator(func(message string) (string, error) {
+		select {
+		case held <- struct{}{}:
+		default:
+		}
+
+		<-release
+
+		return message + ".", nil
+	})
+
+	pipeline := conveyer.New(messages)
+	pipeline.RegisterDecorator(gated, "in", "a")
+	pipeline.RegisterDecorator(gated, "a", "b")
+	pipeline.RegisterDecorator(gated, "b", "out")
+
+	return &pipeline, held
+}
+
+func released() chan struct{} {
+	release := make(chan struct{})
+	close(release)
+
+	return release
+}
+
+func sendAll(t *testing.T, pipeline *conveyer.StringConveyer, count int) {
+	t.Helper()
+
+	for index := range count {
+		require.NoError(t, pipeline.Send("in", fmt.Sprint(index)))
+	}
+}
+
+// requireSendClosed sends to an input that is full and nobody reads,
+// which blocks until Shutdown stops accepting input.
+func requireSendClosed(t *testing.T, pipeline *conveyer.StringConveyer) {
+	t.Helper()
+
+	err := pipeline.Send("in", "late")
+	require.ErrorIs(t, err, conveyer.ErrClosed)
+}
+
+func requireDrained(t *testing.T, pipeline *conveyer.StringConveyer, count int) {
+	t.Helper()
+
+	for index := range count {
+		message, err := pipeline.Recv("out")
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprint(index)+"...", message)
+	}
+
+	message, err := pipeline.Recv("out")
+	require.ErrorIs(t, err, conveyer.ErrClosed)
+	require.Equal(t, "undefined", message)
+}
+
+func TestShutdownDrains(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	pipeline, held := gatedChain(release)
+	result := make(chan error, 1)
+
+	go func() {
+		result <- pipeline.Run(context.Background())
+	}()
+
+	// The first decorator holds one message, so one more fills the input.
+	sendAll(t, pipeline, messages)
+	<-held
+	require.NoError(t, pipeline.Send("in", fmt.Sprint(messages)))
+
+	shutdown := make(chan error, 1)
+
+	go func() {
+		shutdown <- pipeline.Shutdown(context.Background())
+	}()
+
+	requireSendClosed(t, pipeline)
+	close(release)
+
+	requireDrained(t, pipeline, messages+1)
+	require.NoError(t, <-shutdown)
+	require.NoError(t, <-result)
+}
+
+func TestShutdownBeforeRunWaits(t *testing.T) {
+	t.Parallel()
+
+	pipeline, _ := gatedChain(released())
+	sendAll(t, pipeline, messages)
+
+	shutdown := make(chan error, 1)
+
+	go func() {
+		shutdown <- pipeline.Shutdown(context.Background())
+	}()
+
+	requireSendClosed(t, pipeline)
+	require.NoError(t, pipeline.Run(context.Background()), "buffered messages keep Shutdown waiting for Run")
+	require.NoError(t, <-shutdown)
+	requireDrained(t, pipeline, messages)
+}
+
+func TestShutdownBeforeRunDiscards(t *testing.T) {
+	t.Parallel()
+
+	pipeline, _ := gatedChain(released())
+	sendAll(t, pipeline, messages)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := pipeline.Shutdown(ctx)
+
+	var discarded *conveyer.DiscardedError
+
+	require.ErrorAs(t, err, &discarded)
+	require.Equal(t, messages, discarded.Pending)
+	require.ErrorIs(t, err, conveyer.ErrDiscarded)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, pipeline.Run(context.Background()), conveyer.ErrClosed)
+}
+
+func TestShutdownBeforeRunEmpty(t *testing.T) {
+	t.Parallel()
+
+	pipeline, _ := gatedChain(released())
+
+	require.NoError(t, pipeline.Shutdown(context.Background()))
+	require.NoError(t, pipeline.Shutdown(context.Background()))
+	require.ErrorIs(t, pipeline.Run(context.Background()), conveyer.ErrClosed)
+
+	_, err := pipeline.Recv("out")
+	require.ErrorIs(t, err, conveyer.ErrClosed)
+}
+
+func TestClosedError(t *testing.T) {
+	t.Parallel()
+
+	pipeline, _ := gatedChain(released())
+	require.NoError(t, pipeline.Shutdown(context.Background()))
+
+	err := pipeline.Send("in", "late")
+
+	var closed *conveyer.ClosedError
+
+	require.ErrorAs(t, err, &closed)
+	require.Equal(t, conveyer.ClosedError{Op: "send", Channel: "in"}, *closed)
+	require.ErrorIs(t, err, conveyer.ErrClosed)
+
+	_, err = pipeline.Recv("a")
+	require.ErrorAs(t, err, &closed)
+	require.Equal(t, conveyer.ClosedError{Op: "recv", Channel: "a"}, *closed)
+}
+
+func TestShutdownReleasesBlockedSend(t *testing.T) {
+	t.Parallel()
+
+	pipeline := conveyer.New(0)
+	pipeline.RegisterDecorator(handlers.PrefixDecoratorFunc, "in", "out")
+
+	sent := make(chan error, 1)
+
+	go func() {
+		sent <- pipeline.Send("in", "a")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.NoError(t, pipeline.Shutdown(ctx))
+	require.ErrorIs(t, <-sent, conveyer.ErrClosed)
+}
@@ -0,0 +1,209 @@
+package conveyer
+
+import (
+	"context"
+	"reflect"
+)
+
+// tap stands between a handler and its channels so that supervise can
+// tell which message the handler failed on. A single goroutine makes
+// every hand-off in the order the handler makes them: a message the
+// handler takes becomes the last one, and a message it emits clears it,
+// so a failure after a successful hand-off blames no message.
+type tap[T any] struct {
+	inputs         []chan T
+	outputs        []chan T
+	handlerInputs  []chan T
+	handlerOutputs []chan T
+	queries        chan chan taken[T]
+	flush          chan struct{}
+	done           chan struct{}
+}
+
+type taken[T any] struct {
+	message T
+	ok      bool
+}
+
+// slot is a message the tap has received and not yet passed on.
+type slot[T any] struct {
+	message T
+	full    bool
+	closed  bool
+}
+
+func newTap[T any](inputs, outputs []chan T) *tap[T] {
+	return &tap[T]{
+		inputs:         inputs,
+		outputs:        outputs,
+		handlerInputs:  makeChannels[T](len(inputs)),
+		handlerOutputs: makeChannels[T](len(outputs)),
+		queries:        make(chan chan taken[T]),
+		flush:          make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+}
+
+func makeChannels[T any](count int) []chan T {
+	channels := make([]chan T, 0, count)
+	for range count {
+		channels = append(channels, make(chan T))
+	}
+
+	return channels
+}
+
+const (
+	caseDone = iota
+	caseQuery
+	caseFlush
+	fixedCases
+)
+
+// run moves messages until ctx ends or stop is called. Each input and
+// output has one slot, so the tap adds no more buffering than that.
+func (t *tap[T]) run(ctx context.Context) {
+	defer close(t.done)
+
+	var (
+		last     taken[T]
+		inbound  = make([]slot[T], len(t.inputs))
+		outbound = make([]slot[T], len(t.outputs))
+	)
+
+	for {
+		cases, owners := t.cases(ctx, inbound, outbound)
+
+		chosen, received, ok := reflect.Select(cases)
+
+		switch {
+		case chosen == caseDone:
+			return
+		case chosen == caseQuery:
+			reply, _ := received.Interface().(chan taken[T])
+			reply <- last
+			last.ok = false
+		case chosen == caseFlush:
+			t.forward(ctx, outbound)
+
+			return
+		default:
+			owner := owners[chosen-fixedCases]
+
+			switch {
+			case owner.input && owner.send:
+				last = taken[T]{message: inbound[owner.index].message, ok: true}
+				inbound[owner.index].full = false
+			case owner.input && !ok:
+				inbound[owner.index].closed = true
+				close(t.handlerInputs[owner.index])
+			case owner.input:
+				inbound[owner.index] = slot[T]{message: value[T](received), full: true, closed: false}
+			case owner.send:
+				outbound[owner.index].full = false
+			default:
+				last.ok = false
+				outbound[owner.index] = slot[T]{message: value[T](received), full: true, closed: false}
+			}
+		}
+	}
+}
+
+// owner says which channel a select case after the fixed ones is for.
+type owner struct {
+	index int
+	input bool
+	send  bool
+}
+
+func (t *tap[T]) cases(ctx context.Context, inbound, outbound []slot[T]) ([]reflect.SelectCase, []owner) {
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done()), Send: reflect.Value{}},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(t.queries), Send: reflect.Value{}},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(t.flush), Send: reflect.Value{}},
+	}
+	owners := make([]owner, 0, len(inbound)+len(outbound))
+
+	for index, current := range inbound {
+		switch {
+		case current.closed:
+			continue
+		case current.full:
+			cases = append(cases, sendCase(t.handlerInputs[index], current.message))
+		default:
+			cases = append(cases, recvCase(t.inputs[index]))
+		}
+
+		owners = append(owners, owner{index: index, input: true, send: current.full})
+	}
+
+	for index, current := range outbound {
+		if current.full {
+			cases = append(cases, sendCase(t.outputs[index], current.message))
+		} else {
+			cases = append(cases, recvCase(t.handlerOutputs[index]))
+		}
+
+		owners = append(owners, owner{index: index, input: false, send: current.full})
+	}
+
+	return cases, owners
+}
+
+func recvCase[T any](channel chan T) reflect.SelectCase {
+	return reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(channel), Send: reflect.Value{}}
+}
+
+// sendCase goes through a pointer so that a nil interface message is
+// still a valid value to send.
+func sendCase[T any](channel chan T, message T) reflect.SelectCase {
+	return reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(channel), Send: reflect.ValueOf(&message).Elem()}
+}
+
+func value[T any](received reflect.Value) T {
+	message, _ := received.Interface().(T)
+
+	return message
+}
+
+// forward passes on what the handler emitted before it exited, so that
+// nothing is lost when its outputs are closed.
+func (t *tap[T]) forward(ctx context.Context, outbound []slot[T]) {
+	for index, current := range outbound {
+		if current.full && !sendContext(ctx, t.outputs[index], current.message) {
+			return
+		}
+	}
+}
+
+// last returns the message the handler took since it last emitted one or
+// since the previous call, if any.
+func (t *tap[T]) last(ctx context.Context) (T, bool) {
+	reply := make(chan taken[T], 1)
+
+	select {
+	case <-ctx.Done():
+		var none T
+
+		return none, false
+	case <-t.done:
+		var none T
+
+		return none, false
+	case t.queries <- reply:
+	}
+
+	answer := <-reply
+
+	return answer.message, answer.ok
+}
+
+// stop waits for the tap to pass on what it holds and exit.
+func (t *tap[T]) stop() {
+	select {
+	case t.flush <- struct{}{}:
+	case <-t.done:
+	}
+
+	<-t.done
+}
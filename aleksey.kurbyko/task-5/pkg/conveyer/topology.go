@@ -0,0 +1,189 @@
+package conveyer
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidTopology = errors.New("invalid topology")
+
+type ProblemKind string
+
+const (
+	NoProducer        ProblemKind = "no producer"
+	NoConsumer        ProblemKind = "no consumer"
+	Cycle             ProblemKind = "cycle"
+	DuplicateConsumer ProblemKind = "duplicate consumer"
+)
+
+// Problem is one defect found by Validate. Path is set for cycles and
+// lists the channels in order, starting and ending with Channel.
+type Problem struct {
+	Kind    ProblemKind
+	Channel string
+	Path    []string
+}
+
+func (p Problem) String() string {
+	if p.Kind == Cycle {
+		return fmt.Sprintf("%s: %s", p.Kind, strings.Join(p.Path, " -> "))
+	}
+
+	return fmt.Sprintf("channel %q: %s", p.Channel, p.Kind)
+}
+
+type TopologyError struct {
+	Problems []Problem
+}
+
+func (e *TopologyError) Error() string {
+	parts := make([]string, 0, len(e.Problems))
+	for _, problem := range e.Problems {
+		parts = append(parts, problem.String())
+	}
+
+	return fmt.Sprintf("%s: %s", ErrInvalidTopology, strings.Join(parts, "; "))
+}
+
+func (e *TopologyError) Unwrap() error {
+	return ErrInvalidTopology
+}
+
+type node struct {
+	inputs   []string
+	outputs  []string
+	options  options
+	counters *counters
+}
+
+// DeclareInputs marks channels that are fed with Send. Once any endpoint
+// is declared, Validate reports every other channel without a producer.
+func (c *Conveyer[T]) DeclareInputs(names ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.makeChannels(names...)
+
+	for _, name := range names {
+		c.sendEndpoints[name] = true
+	}
+}
+
+// DeclareOutputs marks channels that are drained with Recv. Once any
+// endpoint is declared, Validate reports every other channel without a
+// consumer.
+func (c *Conveyer[T]) DeclareOutputs(names ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.makeChannels(names...)
+
+	for _, name := range names {
+		c.recvEndpoints[name] = true
+	}
+}
+
+// Validate checks the registered handlers as a graph of channels. Without
+// declared endpoints, channels nobody produces are taken as Send inputs
+// and channels nobody consumes as Recv outputs, so only cycles and
+// duplicate consumers can be found; declare the endpoints to have a
+// misspelt channel name reported.
+func (c *Conveyer[T]) Validate() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	producers := make(map[string]int)
+	consumers := make(map[string]int)
+	next := make(map[string][]string)
+
+	for _, registered := range c.nodes {
+		for _, input := range registered.inputs {
+			consumers[input]++
+			next[input] = append(next[input], registered.outputs...)
+		}
+
+		for _, output := range registered.outputs {
+			producers[output]++
+		}
+	}
+
+	names := make([]string, 0, len(c.channels))
+	for name := range c.channels {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	declared := len(c.sendEndpoints) > 0 || len(c.recvEndpoints) > 0
+
+	var problems []Problem
+
+	for _, name := range names {
+		if declared && producers[name] == 0 && !c.sendEndpoints[name] {
+			problems = append(problems, Problem{Kind: NoProducer, Channel: name, Path: nil})
+		}
+
+		if declared && consumers[name] == 0 && !c.recvEndpoints[name] {
+			problems = append(problems, Problem{Kind: NoConsumer, Channel: name, Path: nil})
+		}
+
+		if consumers[name] > 1 {
+			problems = append(problems, Problem{Kind: DuplicateConsumer, Channel: name, Path: nil})
+		}
+	}
+
+	problems = append(problems, findCycles(names, next)...)
+
+	if len(problems) > 0 {
+		return &TopologyError{Problems: problems}
+	}
+
+	return nil
+}
+
+const (
+	unvisited = iota
+	onStack
+	done
+)
+
+// findCycles runs a depth-first search over the channel graph and reports
+// each back edge as one cycle.
+func findCycles(names []string, next map[string][]string) []Problem {
+	state := make(map[string]int, len(names))
+
+	var (
+		problems []Problem
+		stack    []string
+		visit    func(name string)
+	)
+
+	visit = func(name string) {
+		state[name] = onStack
+		stack = append(stack, name)
+
+		for _, target := range next[name] {
+			switch state[target] {
+			case onStack:
+				start := slices.Index(stack, target)
+				path := append(slices.Clone(stack[start:]), target)
+				problems = append(problems, Problem{Kind: Cycle, Channel: target, Path: path})
+			case unvisited:
+				visit(target)
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = done
+	}
+
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(name)
+		}
+	}
+
+	return problems
+}
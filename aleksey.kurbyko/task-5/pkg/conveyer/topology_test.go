@@ -0,0 +1,143 @@
+package conveyer_test
+
+import (
+	"context"
+	"testing"
+
+	"aleksey.kurbyko/task-5/pkg/conveyer"
+	"aleksey.kurbyko/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+type edge struct {
+	inputs  []string
+	outputs []string
+}
+
+func build(edges []edge, inputs, outputs []string) *conveyer.StringConveyer {
+	pipeline := conveyer.New(0)
+
+	for _, current := range edges {
+		switch {
+		case len(current.inputs) > 1:
+			pipeline.RegisterMultiplexer(handlers.MultiplexerFunc, current.inputs, current.outputs[0])
+		case len(current.outputs) > 1:
+			pipeline.RegisterSeparator(handlers.SeparatorFunc, current.inputs[0], current.outputs)
+		default:
+			pipeline.RegisterDecorator(handlers.PrefixDecoratorFunc, current.inputs[0], current.outputs[0])
+		}
+	}
+
+	if len(inputs) > 0 {
+		pipeline.DeclareInputs(inputs...)
+	}
+
+	if len(outputs) > 0 {
+		pipeline.DeclareOutputs(outputs...)
+	}
+
+	return &pipeline
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name     string
+		edges    []edge
+		inputs   []string
+		outputs  []string
+		problems []conveyer.Problem
+	}{
+		{
+			name: "clean graph",
+			edges: []edge{
+				{inputs: []string{"a"}, outputs: []string{"b"}},
+				{inputs: []string{"b"}, outputs: []string{"c", "d"}},
+				{inputs: []string{"c", "d"}, outputs: []string{"e"}},
+			},
+			problems: nil,
+		},
+		{
+			name: "independent graphs declared",
+			edges: []edge{
+				{inputs: []string{"a"}, outputs: []string{"b"}},
+				{inputs: []string{"x"}, outputs: []string{"y"}},
+			},
+			inputs:   []string{"a", "x"},
+			outputs:  []string{"b", "y"},
+			problems: nil,
+		},
+		{
+			name: "disconnected chains",
+			edges: []edge{
+				{inputs: []string{"a"}, outputs: []string{"b"}},
+				{inputs: []string{"x"}, outputs: []string{"y"}},
+			},
+			problems: nil,
+		},
+		{
+			name: "typo with declared endpoints",
+			edges: []edge{
+				{inputs: []string{"a"}, outputs: []string{"b"}},
+				{inputs: []string{"bb"}, outputs: []string{"c"}},
+			},
+			inputs:  []string{"a"},
+			outputs: []string{"c"},
+			problems: []conveyer.Problem{
+				{Kind: conveyer.NoConsumer, Channel: "b", Path: nil},
+				{Kind: conveyer.NoProducer, Channel: "bb", Path: nil},
+			},
+		},
+		{
+			name: "cycle",
+			edges: []edge{
+				{inputs: []string{"a"}, outputs: []string{"b"}},
+				{inputs: []string{"b", "in"}, outputs: []string{"a"}},
+			},
+			problems: []conveyer.Problem{
+				{Kind: conveyer.Cycle, Channel: "a", Path: []string{"a", "b", "a"}},
+			},
+		},
+		{
+			name: "duplicate consumer",
+			edges: []edge{
+				{inputs: []string{"a"}, outputs: []string{"b"}},
+				{inputs: []string{"a"}, outputs: []string{"c"}},
+			},
+			problems: []conveyer.Problem{
+				{Kind: conveyer.DuplicateConsumer, Channel: "a", Path: nil},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := build(test.edges, test.inputs, test.outputs).Validate()
+			if test.problems == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var topology *conveyer.TopologyError
+
+			require.ErrorAs(t, err, &topology)
+			require.ErrorIs(t, err, conveyer.ErrInvalidTopology)
+			require.Equal(t, test.problems, topology.Problems)
+		})
+	}
+}
+
+func TestRunValidates(t *testing.T) {
+	t.Parallel()
+
+	pipeline := build([]edge{
+		{inputs: []string{"a"}, outputs: []string{"b"}},
+		{inputs: []string{"bb"}, outputs: []string{"c"}},
+	}, []string{"a"}, []string{"c"})
+
+	err := pipeline.Run(context.Background())
+	require.ErrorIs(t, err, conveyer.ErrInvalidTopology)
+	require.ErrorContains(t, err, `channel "bb": no producer`)
+}
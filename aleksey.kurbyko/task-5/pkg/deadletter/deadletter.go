@@ -0,0 +1,37 @@
+// Package deadletter lets a handler report a message it rejects to
+// whatever runs it, without depending on the conveyer.
+package deadletter
+
+import "context"
+
+// Rejecter records a rejected message with the reason. Reject reports
+// whether the handler should carry on; false means the handler should
+// fail with reason.
+type Rejecter[T any] interface {
+	Reject(ctx context.Context, message T, reason error) bool
+}
+
+type rejecterKey struct{}
+
+// NewContext returns a context that carries rejecter to the handler.
+func NewContext[T any](ctx context.Context, rejecter Rejecter[T]) context.Context {
+	return context.WithValue(ctx, rejecterKey{}, rejecter)
+}
+
+// FromContext returns the rejecter ctx carries for messages of type T.
+func FromContext[T any](ctx context.Context) (Rejecter[T], bool) {
+	rejecter, ok := ctx.Value(rejecterKey{}).(Rejecter[T])
+
+	return rejecter, ok
+}
+
+// Reject reports message to the rejecter in ctx. Without one it returns
+// false, so the handler fails with reason.
+func Reject[T any](ctx context.Context, message T, reason error) bool {
+	rejecter, ok := FromContext[T](ctx)
+	if !ok {
+		return false
+	}
+
+	return rejecter.Reject(ctx, message, reason)
+}
@@ -0,0 +1,40 @@
+package deadletter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"aleksey.kurbyko/task-5/pkg/deadletter"
+	"github.com/stretchr/testify/require"
+)
+
+var errReason = errors.New("reason")
+
+type rejecter struct {
+	message string
+	reason  error
+}
+
+func (r *rejecter) Reject(_ context.Context, message string, reason error) bool {
+	r.message, r.reason = message, reason
+
+	return true
+}
+
+func TestReject(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, deadletter.Reject(context.Background(), "a", errReason), "nobody to reject to")
+
+	recorded := &rejecter{message: "", reason: nil}
+	ctx := deadletter.NewContext[string](context.Background(), recorded)
+
+	require.True(t, deadletter.Reject(ctx, "a", errReason))
+	require.Equal(t, "a", recorded.message)
+	require.ErrorIs(t, recorded.reason, errReason)
+
+	_, ok := deadletter.FromContext[int](ctx)
+	require.False(t, ok, "a rejecter is only found for its message type")
+	require.False(t, deadletter.Reject(ctx, 1, errReason))
+}
@@ -5,11 +5,14 @@ import (
 	"errors"
 	"strings"
 	"sync"
+
+	"aleksey.kurbyko/task-5/pkg/deadletter"
 )
 
 var (
-	ErrCantBeDecorated = errors.New("can't be decorated")
-	ErrEmptyOutputs    = errors.New("empty outputs")
+	ErrCantBeDecorated   = errors.New("can't be decorated")
+	ErrCantBeMultiplexed = errors.New("can't be multiplexed")
+	ErrEmptyOutputs      = errors.New("empty outputs")
 )
 
 const (
@@ -23,36 +26,83 @@ func PrefixDecoratorFunc(
 	input chan string,
 	output chan string,
 ) error {
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case message, ok := <-input:
-			if !ok {
-				return nil
-			}
+	return Decorator(prefixMessage)(ctx, input, output)
+}
 
-			if strings.Contains(message, noDecoratorMark) {
-				return ErrCantBeDecorated
-			}
+func SeparatorFunc(
+	ctx context.Context,
+	input chan string,
+	outputs []chan string,
+) error {
+	return Separate(ctx, input, outputs)
+}
 
-			if !strings.HasPrefix(message, decoratedPrefix) {
-				message = decoratedPrefix + message
-			}
+func MultiplexerFunc(
+	ctx context.Context,
+	inputs []chan string,
+	output chan string,
+) error {
+	return Multiplexer(func(message string) error {
+		if strings.Contains(message, noMultiplexMark) {
+			return ErrCantBeMultiplexed
+		}
 
+		return nil
+	})(ctx, inputs, output)
+}
+
+func prefixMessage(message string) (string, error) {
+	if strings.Contains(message, noDecoratorMark) {
+		return "", ErrCantBeDecorated
+	}
+
+	if !strings.HasPrefix(message, decoratedPrefix) {
+		message = decoratedPrefix + message
+	}
+
+	return message, nil
+}
+
+// Decorator builds a decorator handler that passes every message through
+// decorate. A message decorate fails on is rejected, and the handler
+// stops with the error unless the rejecter lets it carry on.
+func Decorator[T any](
+	decorate func(message T) (T, error),
+) func(ctx context.Context, input chan T, output chan T) error {
+	return func(ctx context.Context, input chan T, output chan T) error {
+		for {
 			select {
 			case <-ctx.Done():
 				return nil
-			case output <- message:
+			case message, ok := <-input:
+				if !ok {
+					return nil
+				}
+
+				decorated, err := decorate(message)
+				if err != nil {
+					if deadletter.Reject(ctx, message, err) {
+						continue
+					}
+
+					return err
+				}
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case output <- decorated:
+				}
 			}
 		}
 	}
 }
 
-func SeparatorFunc(
+// Separate spreads messages over outputs in round-robin order.
+func Separate[T any](
 	ctx context.Context,
-	input chan string,
-	outputs []chan string,
+	input chan T,
+	outputs []chan T,
 ) error {
 	if len(outputs) == 0 {
 		return ErrEmptyOutputs
@@ -81,51 +131,58 @@ func SeparatorFunc(
 	}
 }
 
-func MultiplexerFunc(
-	ctx context.Context,
-	inputs []chan string,
-	output chan string,
-) error {
-	if len(inputs) == 0 {
-		return nil
-	}
-
-	var waitGroup sync.WaitGroup
+// Multiplexer builds a multiplexer handler that merges inputs into output.
+// Messages check rejects are filtered out and reported to the rejecter in
+// ctx, which counts and dead-letters them as the handler's policy says;
+// filtering is what the handler is for, so it carries on either way.
+// Without a rejecter they are dropped.
+func Multiplexer[T any](
+	check func(message T) error,
+) func(ctx context.Context, inputs []chan T, output chan T) error {
+	return func(ctx context.Context, inputs []chan T, output chan T) error {
+		if len(inputs) == 0 {
+			return nil
+		}
 
-	waitGroup.Add(len(inputs))
+		var waitGroup sync.WaitGroup
 
-	for _, inputChannel := range inputs {
-		currentInput := inputChannel
+		waitGroup.Add(len(inputs))
 
-		worker := func() {
-			defer waitGroup.Done()
+		for _, inputChannel := range inputs {
+			currentInput := inputChannel
 
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case message, ok := <-currentInput:
-					if !ok {
-						return
-					}
-
-					if strings.Contains(message, noMultiplexMark) {
-						continue
-					}
+			worker := func() {
+				defer waitGroup.Done()
 
+				for {
 					select {
 					case <-ctx.Done():
 						return
-					case output <- message:
+					case message, ok := <-currentInput:
+						if !ok {
+							return
+						}
+
+						if err := check(message); err != nil {
+							deadletter.Reject(ctx, message, err)
+
+							continue
+						}
+
+						select {
+						case <-ctx.Done():
+							return
+						case output <- message:
+						}
 					}
 				}
 			}
-		}
 
-		go worker()
-	}
+			go worker()
+		}
 
-	waitGroup.Wait()
+		waitGroup.Wait()
 
-	return nil
+		return nil
+	}
 }
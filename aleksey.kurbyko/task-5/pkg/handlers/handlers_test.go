@@ -0,0 +1,127 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"aleksey.kurbyko/task-5/pkg/deadletter"
+	"aleksey.kurbyko/task-5/pkg/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a rejecter that keeps what it is given.
+type recorder struct {
+	carryOn  bool
+	messages chan string
+}
+
+func (r *recorder) Reject(_ context.Context, message string, _ error) bool {
+	r.messages <- message
+
+	return r.carryOn
+}
+
+func feed(messages ...string) chan string {
+	channel := make(chan string, len(messages))
+	for _, message := range messages {
+		channel <- message
+	}
+
+	close(channel)
+
+	return channel
+}
+
+func drain(channel chan string) []string {
+	close(channel)
+
+	var received []string
+	for message := range channel {
+		received = append(received, message)
+	}
+
+	return received
+}
+
+func TestPrefixDecorator(t *testing.T) {
+	t.Parallel()
+
+	output := make(chan string, 2)
+
+	require.NoError(t, handlers.PrefixDecoratorFunc(context.Background(), feed("a", "decorated: b"), output))
+	require.Equal(t, []string{"decorated: a", "decorated: b"}, drain(output))
+}
+
+func TestPrefixDecoratorRejects(t *testing.T) {
+	t.Parallel()
+
+	output := make(chan string, 2)
+	err := handlers.PrefixDecoratorFunc(context.Background(), feed("no decorator", "a"), output)
+	require.ErrorIs(t, err, handlers.ErrCantBeDecorated)
+
+	rejected := &recorder{carryOn: true, messages: make(chan string, 1)}
+	ctx := deadletter.NewContext[string](context.Background(), rejected)
+	output = make(chan string, 2)
+
+	require.NoError(t, handlers.PrefixDecoratorFunc(ctx, feed("no decorator", "a"), output))
+	require.Equal(t, []string{"decorated: a"}, drain(output))
+	require.Equal(t, []string{"no decorator"}, drain(rejected.messages))
+}
+
+func TestSeparator(t *testing.T) {
+	t.Parallel()
+
+	outputs := []chan string{make(chan string, 2), make(chan string, 2)}
+
+	require.NoError(t, handlers.SeparatorFunc(context.Background(), feed("a", "b", "c"), outputs))
+	require.Equal(t, []string{"a", "c"}, drain(outputs[0]))
+	require.Equal(t, []string{"b"}, drain(outputs[1]))
+
+	require.ErrorIs(t, handlers.SeparatorFunc(context.Background(), feed(), nil), handlers.ErrEmptyOutputs)
+}
+
+func TestMultiplexer(t *testing.T) {
+	t.Parallel()
+
+	output := make(chan string, 3)
+	inputs := []chan string{feed("a", "no multiplexer"), feed("b")}
+	rejected := &recorder{carryOn: false, messages: make(chan string, 1)}
+	ctx := deadletter.NewContext[string](context.Background(), rejected)
+
+	require.NoError(t, handlers.MultiplexerFunc(ctx, inputs, output), "filtering goes on whatever the rejecter says")
+
+	merged := drain(output)
+	slices.Sort(merged)
+	require.Equal(t, []string{"a", "b"}, merged)
+	require.Equal(t, []string{"no multiplexer"}, drain(rejected.messages))
+}
+
+func TestMultiplexerWithoutRejecter(t *testing.T) {
+	t.Parallel()
+
+	output := make(chan string, 3)
+	inputs := []chan string{feed("a", "no multiplexer", "b")}
+
+	require.NoError(t, handlers.MultiplexerFunc(context.Background(), inputs, output))
+	require.Equal(t, []string{"a", "b"}, drain(output), "rejected messages are dropped and the rest forwarded")
+
+	multiplexer := handlers.Multiplexer(func(number int) error {
+		if number%2 == 1 {
+			return errors.New("odd")
+		}
+
+		return nil
+	})
+
+	numbers := make(chan int, 3)
+	numbers <- 3
+	numbers <- 2
+	close(numbers)
+
+	merged := make(chan int, 1)
+	require.NoError(t, multiplexer(context.Background(), []chan int{numbers}, merged))
+	require.Equal(t, 2, <-merged)
+	require.NoError(t, multiplexer(context.Background(), nil, merged))
+}